@@ -4,6 +4,7 @@ import (
 	"log"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/simonedbarber/go-template/html/template"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/simonedbarber/qor"
 	"github.com/simonedbarber/qor/resource"
 	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
 	"github.com/simonedbarber/session"
 	"github.com/simonedbarber/session/manager"
 	"github.com/theplant/cldr"
@@ -28,6 +30,14 @@ type AdminConfig struct {
 	SessionManager  session.ManagerInterface
 	SettingsStorage SettingsStorageInterface
 	I18n            I18n
+	// Broadcaster deliver created, updated, deleted events to index pages, default is a MemoryBroadcaster
+	Broadcaster Broadcaster
+	// SharedSavedFilterPermission permission of saving saved filters shared with roles or everyone, including shared default filters, default only allows role admin
+	SharedSavedFilterPermission *roles.Permission
+	// SearchCenterTimeout time budget of searching resources in search center, resources exceed it are reported as timed out, default is DefaultSearchCenterTimeout
+	SearchCenterTimeout time.Duration
+	// SearchCenterLimit max count of results of every resource in search center, default is DefaultSearchCenterLimit
+	SearchCenterLimit int
 	*Transformer
 }
 
@@ -40,6 +50,7 @@ type Admin struct {
 	router           *Router
 	funcMaps         template.FuncMap
 	metaConfigorMaps map[string]func(*Meta)
+	webhooks         *WebhookConfig
 }
 
 // New new admin with configuration
@@ -74,10 +85,26 @@ func New(config interface{}) *Admin {
 		admin.SettingsStorage = newSettings(admin.AdminConfig.DB)
 	}
 
+	if admin.Broadcaster == nil {
+		admin.Broadcaster = NewMemoryBroadcaster()
+	}
+
+	if admin.SharedSavedFilterPermission == nil {
+		admin.SharedSavedFilterPermission = roles.Allow(roles.CRUD, "admin")
+	}
+
+	if admin.SearchCenterTimeout <= 0 {
+		admin.SearchCenterTimeout = DefaultSearchCenterTimeout
+	}
+
+	if admin.SearchCenterLimit <= 0 {
+		admin.SearchCenterLimit = DefaultSearchCenterLimit
+	}
+
 	admin.SetAssetFS(admin.AssetFS)
 
 	if admin.AdminConfig.DB != nil {
-		admin.AdminConfig.DB.AutoMigrate(&QorAdminSetting{})
+		admin.AdminConfig.DB.AutoMigrate(&QorAdminSetting{}, &QorAdminAuditLog{})
 	}
 
 	admin.registerCompositePrimaryKeyCallback()
@@ -219,11 +246,30 @@ func (admin *Admin) AddResource(value interface{}, config ...*Config) *Resource
 			Modes:      []string{"menu_item"},
 		})
 
+		if !res.Config.Singleton {
+			res.registerDuplicateAction()
+		}
+
+		if res.Config.BulkDelete && !res.Config.Singleton {
+			res.Action(&Action{
+				Name:       "Bulk Delete",
+				Label:      "Delete",
+				Permission: res.Config.Permission,
+				Modes:      []string{"batch"},
+				Visible: func(record interface{}, context *Context) bool {
+					return !isTrashRequest(context) && res.HasPermission(roles.Delete, context.Context)
+				},
+				Handler: func(argument *ActionArgument) error {
+					return res.deleteRecords(argument.PrimaryValues, argument.Context)
+				},
+			})
+		}
+
 		menuName := res.Name
 		if !res.Config.Singleton {
 			menuName = inflection.Plural(res.Name)
 		}
-		admin.AddMenu(&Menu{Name: menuName, IconName: res.Config.IconName, Permissioner: res, Priority: res.Config.Priority, Ancestors: res.Config.Menu, RelativePath: res.ToParam()})
+		admin.AddMenu(&Menu{Name: menuName, IconName: res.Config.IconName, Permissioner: res, Priority: res.Config.Priority, Ancestors: res.Config.Menu, RelativePath: res.ToParam(), Badge: res.resourceMenuBadge})
 
 		admin.RegisterResourceRouters(res, "create", "update", "read", "delete")
 	}
@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// QorAdminAuditLog audit entry of operations made in admin, like merging records
+type QorAdminAuditLog struct {
+	gorm.Model
+	Action   string
+	Resource string
+	RecordID string
+	UserID   string
+	UserName string
+	Detail   string `gorm:"size:65532"`
+}
+
+// addAuditLog save an audit entry with db, detail is saved as JSON
+func (context *Context) addAuditLog(db *gorm.DB, action string, res *Resource, recordID interface{}, detail interface{}) error {
+	value, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	log := QorAdminAuditLog{Action: action, RecordID: fmt.Sprint(recordID), Detail: string(value)}
+	if res != nil {
+		log.Resource = res.ToParam()
+	}
+
+	if context.CurrentUser != nil {
+		log.UserID = fmt.Sprint(context.CurrentUser.GetID())
+		log.UserName = context.CurrentUser.DisplayName()
+	}
+	return db.Create(&log).Error
+}
@@ -0,0 +1,285 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/resource"
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+)
+
+// BulkEditActionName name of the action opens bulk edit form of selected records
+const BulkEditActionName = "Bulk Update"
+
+// BulkEditAllActionName name of the action opens bulk edit form of all records matching current filters
+const BulkEditAllActionName = "Bulk Update All"
+
+// BulkEditFieldsParam param of attributes chosen to change with bulk edit
+const BulkEditFieldsParam = "bulk_edit_fields[]"
+
+// BulkEditConfig bulk edit configuration of a resource, refer Resource.UseBulkEdit
+type BulkEditConfig struct {
+	// Attrs attributes could be changed in bulk, default is EditAttrs
+	Attrs []string
+	// Permission permission of bulk edit, it is checked with roles.Update, resource's update permission is required as well
+	Permission *roles.Permission
+}
+
+// UseBulkEdit add action Bulk Update to resource, which opens a form of attributes to change selected records, or all records matching current filters for resources with one primary key, e.g:
+//
+//	product.UseBulkEdit(&admin.BulkEditConfig{
+//		Attrs: []string{"Category", "Price", "Enabled"},
+//	})
+//
+// Only attributes with "change this" checked are applied, values are set with metas' Setter and saved with CallSave record by record, failed records are reported without affecting others
+func (res *Resource) UseBulkEdit(config *BulkEditConfig) {
+	if config == nil {
+		config = &BulkEditConfig{}
+	}
+
+	if res.Config.Singleton {
+		utils.ExitWithMsg("singleton resource %v couldn't be edited in bulk", res.Name)
+	}
+	res.bulkEdit = config
+
+	res.Action(&Action{
+		Name:        BulkEditActionName,
+		Method:      "GET",
+		URLOpenType: "slideout",
+		Modes:       []string{"batch"},
+		URL: func(record interface{}, context *Context) string {
+			return path.Join(context.URLFor(res), "!bulk_edit") + bulkEditQuery(context, false)
+		},
+		Visible: func(record interface{}, context *Context) bool {
+			return res.canBulkEdit(context)
+		},
+	})
+
+	res.Action(&Action{
+		Name:        BulkEditAllActionName,
+		Method:      "GET",
+		URLOpenType: "slideout",
+		Modes:       []string{"collection"},
+		URL: func(record interface{}, context *Context) string {
+			return path.Join(context.URLFor(res), "!bulk_edit") + bulkEditQuery(context, true)
+		},
+		Visible: func(record interface{}, context *Context) bool {
+			return res.canBulkEdit(context) && len(utils.NewScope(res.Value).PrimaryFields) == 1
+		},
+	})
+
+	controller := &Controller{Admin: res.GetAdmin()}
+	res.RegisterRoute("GET", "/!bulk_edit", controller.BulkEdit, &RouteConfig{PermissionMode: roles.Update})
+	res.RegisterRoute("POST", "/!bulk_edit", controller.BulkEdit, &RouteConfig{PermissionMode: roles.Update})
+}
+
+// bulkEditQuery return current index page's scopes, filters and keyword, so bulk edit could find all matching records, all matching records are changed only if all is true
+func bulkEditQuery(context *Context, all bool) string {
+	if context.Request == nil {
+		return ""
+	}
+
+	query := url.Values{}
+	if all {
+		query.Set("all", "true")
+	}
+	for key, values := range context.Request.URL.Query() {
+		if key == "scopes" || key == "keyword" || key == FilterGroupParam || filterRegexp.MatchString(key) {
+			query[key] = values
+		}
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// canBulkEdit check if current user could edit records of the resource in bulk
+func (res *Resource) canBulkEdit(context *Context) bool {
+	if res.bulkEdit == nil || !res.HasPermission(roles.Update, context.Context) {
+		return false
+	}
+
+	if res.bulkEdit.Permission != nil {
+		var userRoles []interface{}
+		for _, role := range context.Roles {
+			userRoles = append(userRoles, role)
+		}
+		return res.bulkEdit.Permission.HasPermission(roles.Update, userRoles...)
+	}
+	return true
+}
+
+// bulkEditMetas metas could be changed in bulk by current user
+func (res *Resource) bulkEditMetas(context *Context) (metas []*Meta) {
+	for _, meta := range res.ConvertSectionToMetas(res.allowedSections(res.EditAttrs(), context, roles.Update)) {
+		if res.bulkEdit == nil || len(res.bulkEdit.Attrs) == 0 || isContainsColumn(res.bulkEdit.Attrs, meta.Name) {
+			metas = append(metas, meta)
+		}
+	}
+	return
+}
+
+// parseBulkEditFields return names of attributes chosen to change, attributes not available are ignored
+func parseBulkEditFields(form url.Values, available []string) (fields []string) {
+	for _, name := range form[BulkEditFieldsParam] {
+		if isContainsColumn(available, name) && !isContainsColumn(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+	return
+}
+
+// BulkEditField an attribute of bulk edit form
+type BulkEditField struct {
+	Meta     *Meta
+	Sections []*Section
+	Changed  bool
+}
+
+// BulkEditResult result of a record changed with bulk edit
+type BulkEditResult struct {
+	PrimaryValue string
+	Errors       []string `json:",omitempty"`
+}
+
+// bulkEditView data of bulk edit form
+type bulkEditView struct {
+	Record        interface{}
+	Query         string
+	Fields        []BulkEditField
+	PrimaryValues []string
+	All           bool
+	Count         int64
+	Results       []BulkEditResult
+	Failed        int
+}
+
+// bulkEditPrimaryValues return primary values of records to change, which are selected records, or records matching current scopes, filters and keyword if all is true
+func (res *Resource) bulkEditPrimaryValues(context *Context, all bool) ([]string, error) {
+	if !all {
+		return context.Request.Form["primary_values[]"], nil
+	}
+
+	var (
+		primaryValues []string
+		scope         = utils.NewScope(res.Value)
+		qorCtx        = res.bulkEditMatchingRecords(context)
+	)
+
+	// records with composite primary keys can't be found with values of one field, they could be changed as selected records
+	if len(scope.PrimaryFields) != 1 {
+		return nil, fmt.Errorf("all matching records of resource %v can't be changed in bulk, it doesn't have one primary key", res.Name)
+	}
+
+	err := qorCtx.GetDB().Model(res.Value).Pluck(scope.PrimaryFields[0].DBName, &primaryValues).Error
+	return primaryValues, err
+}
+
+// bulkEditCount return count of records to change, without loading them
+func (res *Resource) bulkEditCount(context *Context, all bool) (count int64, err error) {
+	if !all {
+		return int64(len(context.Request.Form["primary_values[]"])), nil
+	}
+
+	err = res.bulkEditMatchingRecords(context).GetDB().Model(res.Value).Count(&count).Error
+	return count, err
+}
+
+// bulkEditMatchingRecords return context queries records matching current scopes, filters and keyword
+func (res *Resource) bulkEditMatchingRecords(context *Context) *qor.Context {
+	var (
+		searcher = &Searcher{Context: context}
+		qorCtx   = context.Context.Clone()
+	)
+	return searcher.parseRequest(qorCtx).filterData(qorCtx, true)
+}
+
+// bulkEditMetaValues decode values of chosen attributes from request
+func bulkEditMetaValues(context *Context, metas []*Meta, fields []string) (*resource.MetaValues, error) {
+	var metaors []resource.Metaor
+	for _, meta := range metas {
+		if isContainsColumn(fields, meta.Name) {
+			metaors = append(metaors, meta)
+		}
+	}
+	return resource.ConvertFormToMetaValues(context.Request, metaors, "QorResource.")
+}
+
+// BulkEditRecords set values of metaValues to records with primary values by metas' Setter and save them, each record is saved in its own transaction, so failed records don't affect others
+func (res *Resource) BulkEditRecords(primaryValues []string, metaValues *resource.MetaValues, context *Context) (results []BulkEditResult) {
+	var (
+		updated        []string
+		updatedRecords []interface{}
+	)
+	for _, primaryValue := range primaryValues {
+		var (
+			result = BulkEditResult{PrimaryValue: primaryValue}
+			record = res.NewStruct()
+		)
+
+		err := context.GetDB().Transaction(func(tx *gorm.DB) error {
+			if err := context.checkEditLocks(primaryValue); err != nil {
+				return err
+			}
+
+			qorCtx := context.Context.Clone()
+			qorCtx.SetDB(tx)
+			qorCtx.ResourceID = primaryValue
+
+			old, err := res.findOldRecord(qorCtx, HookBeforeUpdate, HookAfterUpdate)
+			if err != nil {
+				return err
+			}
+
+			if err := res.CallFindOne(record, nil, qorCtx); err != nil {
+				return err
+			}
+
+			if err := resource.DecodeToResource(res, record, metaValues, qorCtx).Start(); err != nil {
+				return err
+			}
+			return res.updateRecord(old, record, context, qorCtx)
+		})
+
+		if errs, ok := err.(qor.Errors); ok {
+			for _, e := range errs.GetErrors() {
+				result.Errors = append(result.Errors, e.Error())
+			}
+		} else if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			updated = append(updated, primaryValue)
+			updatedRecords = append(updatedRecords, record)
+		}
+		results = append(results, result)
+	}
+
+	context.publishLiveEvent(res, LiveEventUpdated, updated...)
+	context.triggerWebhooks(res, LiveEventUpdated, updatedRecords...)
+	return results
+}
+
+// bulkEditRecord return a new record filled with submitted values to render the form
+func (res *Resource) bulkEditRecord(metaValues *resource.MetaValues, context *Context) interface{} {
+	record := res.NewStruct()
+	if metaValues == nil {
+		return record
+	}
+
+	for _, metaValue := range metaValues.Values {
+		if metaValue.Meta == nil {
+			continue
+		}
+
+		if setter := metaValue.Meta.GetSetter(); setter != nil {
+			setter(record, metaValue, context.Context)
+		}
+	}
+	return record
+}
@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseBulkEditFields(t *testing.T) {
+	form := url.Values{BulkEditFieldsParam: {"Price", "Code", "Price", "Enabled"}}
+	fields := parseBulkEditFields(form, []string{"Name", "Price", "Enabled"})
+	if !reflect.DeepEqual(fields, []string{"Price", "Enabled"}) {
+		t.Errorf("wrong bulk edit fields, got %v", fields)
+	}
+
+	if fields := parseBulkEditFields(url.Values{}, []string{"Name"}); len(fields) != 0 {
+		t.Errorf("no fields should be changed without choosing, got %v", fields)
+	}
+}
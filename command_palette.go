@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+)
+
+// CommandPaletteLimit max count of entries of command palette, records found with search center are not included
+var CommandPaletteLimit = 20
+
+// commandPaletteEntry an entry of command palette, Type could be menu, new, action, record
+type commandPaletteEntry struct {
+	Type     string
+	Label    string
+	Group    string `json:",omitempty"`
+	URL      string
+	Method   string `json:",omitempty"`
+	OpenType string `json:",omitempty"`
+}
+
+// matchCommandPalette check if label includes every word of keyword
+func matchCommandPalette(label, keyword string) bool {
+	label = strings.ToLower(label)
+	for _, word := range strings.Fields(strings.ToLower(keyword)) {
+		if !strings.Contains(label, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// commandPaletteEntries return entries of command palette matched with keyword: menus could be read, `New X` of resources could be created, collection actions of current resource, and records found by search center
+func (context *Context) commandPaletteEntries() []*commandPaletteEntry {
+	var (
+		query    = context.Request.URL.Query()
+		keyword  = strings.TrimSpace(query.Get("keyword"))
+		entries  = []*commandPaletteEntry{}
+		addEntry = func(entry *commandPaletteEntry) {
+			if len(entries) < CommandPaletteLimit && matchCommandPalette(strings.Join([]string{entry.Group, entry.Label}, " "), keyword) {
+				entries = append(entries, entry)
+			}
+		}
+		addMenus func(menus []*menu, ancestors []string)
+	)
+
+	// menus
+	addMenus = func(menus []*menu, ancestors []string) {
+		for _, m := range menus {
+			if m.IsFavourites {
+				continue
+			}
+
+			label := string(context.t(fmt.Sprintf("qor_admin.menus.%v", m.Name), m.Name))
+			if url := m.URL(); url != "" {
+				addEntry(&commandPaletteEntry{Type: "menu", Label: label, Group: strings.Join(ancestors, " / "), URL: url})
+			}
+			addMenus(m.SubMenus, append(append([]string{}, ancestors...), label))
+		}
+	}
+	addMenus(context.getMenus(), nil)
+
+	// new resources
+	if resources, err := context.getNewResources(); err == nil {
+		for _, res := range resources {
+			if res.Config.Invisible || res.Config.Singleton {
+				continue
+			}
+
+			name := string(context.t(fmt.Sprintf("%v.name", res.ToParam()), res.Name))
+			addEntry(&commandPaletteEntry{
+				Type:  "new",
+				Label: string(context.t("qor_admin.command_palette.new", "New {{.}}", name)),
+				URL:   path.Join(context.URLFor(res), "new"),
+			})
+		}
+	}
+
+	// actions of current resource that don't require records
+	if res := context.Admin.GetResource(query.Get("resource")); res != nil && res.HasPermission(roles.Read, context.Context) {
+		ctx := context.clone().setResource(res)
+		for _, action := range ctx.AllowedActions(res.GetActions(), "collection") {
+			entry := &commandPaletteEntry{
+				Type:     "action",
+				Label:    string(context.t(fmt.Sprintf("%v.actions.%v", res.ToParam(), action.Label), action.Label)),
+				Group:    string(context.t(fmt.Sprintf("%v.name", res.ToParam()), res.Name)),
+				Method:   action.Method,
+				OpenType: action.URLOpenType,
+			}
+
+			if action.URL != nil {
+				entry.URL = action.URL(nil, ctx)
+			} else {
+				entry.URL = path.Join(context.URLFor(res), "!action", action.ToParam())
+			}
+			addEntry(entry)
+		}
+	}
+
+	// records
+	if keyword != "" {
+		for _, result := range context.searchCenterResults() {
+			if result.Results == nil {
+				continue
+			}
+
+			group := string(context.t(fmt.Sprintf("qor_admin.search_center.%v", result.Resource.ToParam()), result.Resource.Name))
+			records := reflect.Indirect(reflect.ValueOf(result.Results))
+			for i := 0; i < records.Len(); i++ {
+				value := records.Index(i).Interface()
+				entries = append(entries, &commandPaletteEntry{
+					Type:  "record",
+					Label: utils.Stringify(value),
+					Group: group,
+					URL:   result.Context.URLFor(value, result.Resource),
+				})
+			}
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,21 @@
+package admin
+
+import "testing"
+
+func TestMatchCommandPalette(t *testing.T) {
+	for _, testCase := range []struct {
+		Label   string
+		Keyword string
+		Matched bool
+	}{
+		{Label: "New Product", Keyword: "", Matched: true},
+		{Label: "New Product", Keyword: "new prod", Matched: true},
+		{Label: "New Product", Keyword: "PRODUCT", Matched: true},
+		{Label: "Settings / Users", Keyword: "users set", Matched: true},
+		{Label: "New Product", Keyword: "new order", Matched: false},
+	} {
+		if matched := matchCommandPalette(testCase.Label, testCase.Keyword); matched != testCase.Matched {
+			t.Errorf("match %v with %v: expect %v, got %v", testCase.Label, testCase.Keyword, testCase.Matched, matched)
+		}
+	}
+}
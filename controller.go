@@ -2,6 +2,8 @@ package admin
 
 import (
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"mime"
 	"net/http"
@@ -10,7 +12,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/simonedbarber/qor/resource"
 	"github.com/simonedbarber/responder"
+	"github.com/simonedbarber/roles"
 	"gorm.io/gorm"
 )
 
@@ -30,42 +34,197 @@ func (ac *Controller) Dashboard(context *Context) {
 
 // Index render index page
 func (ac *Controller) Index(context *Context) {
+	// redirect before finding records, they are found again by the redirected request
+	var redirectURL string
+	responder.With("html", func() {
+		redirectURL = context.indexRedirectURL()
+	}).With([]string{"json", "xml"}, func() {}).Respond(context.Request)
+
+	if redirectURL != "" {
+		http.Redirect(context.Writer, context.Request, redirectURL, http.StatusFound)
+		return
+	}
+
 	result, err := context.FindMany()
 	context.AddError(err)
 
 	responder.With("html", func() {
 		context.Execute("index", result)
 	}).With([]string{"json", "xml"}, func() {
+		context.writePaginationHeaders()
 		context.Encode("index", result)
 	}).Respond(context.Request)
 }
 
+// indexRedirectURL return url index page should be redirected to, blank if it shouldn't be redirected
+func (context *Context) indexRedirectURL() string {
+	// open default saved filter if there is no params
+	if context.Request.URL.RawQuery == "" {
+		if filter, ok := context.defaultSavedFilter(); ok && filter.URL != context.Request.URL.Path {
+			return filter.URL
+		}
+	}
+
+	// redirect search query to the advanced filter's params, so it could be saved, shared and edited in the advanced filter
+	if url, ok := context.searchQueryURL(); ok {
+		return url
+	}
+	return ""
+}
+
+// IndexSettings show, update or reset current user's index settings of a resource
+func (ac *Controller) IndexSettings(context *Context) {
+	settings := context.getIndexSettings()
+
+	if context.Request.Method != "GET" {
+		settings = IndexSettings{}
+		if context.Request.Method != "DELETE" && context.Request.Form.Get("reset") == "" {
+			settings = indexSettingsFromRequest(context)
+		}
+
+		var err error
+		settings, err = context.saveIndexSettings(settings)
+		context.AddError(err)
+	}
+
+	responder.With("html", func() {
+		if context.HasError() {
+			context.Flash(context.Errors.Error(), "error")
+		}
+		http.Redirect(context.Writer, context.Request, context.URLFor(context.Resource), http.StatusFound)
+	}).With("json", func() {
+		type column struct {
+			Name    string
+			Label   string
+			Visible bool
+		}
+
+		var columns []column
+		for _, c := range context.indexColumns() {
+			columns = append(columns, column{Name: c.Name, Label: c.Label, Visible: c.Visible})
+		}
+
+		var errs []string
+		for _, err := range context.GetErrors() {
+			errs = append(errs, err.Error())
+		}
+
+		context.Writer.Header().Set("Content-Type", "application/json")
+		if context.HasError() {
+			context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		}
+		json.NewEncoder(context.Writer).Encode(map[string]interface{}{
+			"Settings":         settings,
+			"AvailableColumns": columns,
+			"Errors":           errs,
+		})
+	}).Respond(context.Request)
+}
+
+// SavedFilters list, save or delete saved filters of a resource, refer savedFilterFromRequest for params of saving, delete with param name
+func (ac *Controller) SavedFilters(context *Context) {
+	switch context.Request.Method {
+	case "POST", "PUT":
+		context.AddError(context.saveSavedFilter(savedFilterFromRequest(context)))
+	case "DELETE":
+		context.AddError(context.deleteSavedFilter(context.Request.Form.Get("name")))
+	}
+
+	responder.With("html", func() {
+		if context.HasError() {
+			context.Flash(context.Errors.Error(), "error")
+		}
+		http.Redirect(context.Writer, context.Request, context.URLFor(context.Resource), http.StatusFound)
+	}).With("json", func() {
+		var errs []string
+		for _, err := range context.GetErrors() {
+			errs = append(errs, err.Error())
+		}
+
+		context.Writer.Header().Set("Content-Type", "application/json")
+		if context.HasError() {
+			context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		}
+
+		filters := context.savedFilters()
+		if filters == nil {
+			filters = []SavedFilter{}
+		}
+		json.NewEncoder(context.Writer).Encode(map[string]interface{}{
+			"SavedFilters": filters,
+			"Errors":       errs,
+		})
+	}).Respond(context.Request)
+}
+
 // SearchCenter render search center page
 func (ac *Controller) SearchCenter(context *Context) {
-	type Result struct {
-		Context  *Context
-		Resource *Resource
-		Results  interface{}
+	searchResults := context.searchCenterResults()
+
+	responder.With("html", func() {
+		context.Execute("search_center", searchResults)
+	}).With("json", func() {
+		context.Writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(context.Writer).Encode(context.searchCenterJSON(searchResults))
+	}).Respond(context.Request)
+}
+
+// CommandPalette return entries of command palette as JSON, filtered with param keyword, param resource is current resource
+func (ac *Controller) CommandPalette(context *Context) {
+	context.Writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(context.Writer).Encode(context.commandPaletteEntries())
+}
+
+// Favourites show or update current user's favourite menus and saved filters, refer updateFavourites for params
+func (ac *Controller) Favourites(context *Context) {
+	favourites := context.getFavourites()
+
+	if context.Request.Method != "GET" {
+		var err error
+		favourites, err = context.saveFavourites(updateFavourites(favourites, context.Request.Form))
+		context.AddError(err)
 	}
-	var searchResults []Result
 
-	for _, res := range context.GetSearchableResources() {
-		var (
-			resourceName = context.Request.URL.Query().Get("resource_name")
-			ctx          = context.clone().setResource(res)
-			searchResult = Result{Context: ctx, Resource: res}
-		)
+	responder.With("html", func() {
+		if context.HasError() {
+			context.Flash(context.Errors.Error(), "error")
+		}
 
-		if resourceName == "" || res.ToParam() == resourceName {
-			searchResult.Results, _ = ctx.FindMany()
+		referrer := context.Request.Referer()
+		if referrer == "" {
+			referrer = path.Join("/", context.Admin.router.Prefix)
 		}
-		searchResults = append(searchResults, searchResult)
-	}
-	context.Execute("search_center", searchResults)
+		http.Redirect(context.Writer, context.Request, referrer, http.StatusFound)
+	}).With("json", func() {
+		type favourite struct {
+			Key  string
+			Name string
+			URL  string
+		}
+
+		var results = []favourite{}
+		for _, m := range context.favouriteMenus() {
+			results = append(results, favourite{Key: m.FavouriteKey, Name: m.Name, URL: m.URL()})
+		}
+
+		context.Writer.Header().Set("Content-Type", "application/json")
+		if context.HasError() {
+			context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		}
+		json.NewEncoder(context.Writer).Encode(results)
+	}).Respond(context.Request)
 }
 
 // New render new page
 func (ac *Controller) New(context *Context) {
+	if primaryValue := context.Request.URL.Query().Get(DuplicateParam); primaryValue != "" {
+		duplicate, err := context.Resource.Duplicate(primaryValue, context)
+		if err == nil {
+			context.Execute("new", duplicate)
+			return
+		}
+		context.Flash(err.Error(), "error")
+	}
 	context.Execute("new", context.Resource.NewStruct())
 }
 
@@ -75,18 +234,22 @@ func (ac *Controller) Create(context *Context) {
 	status := http.StatusCreated
 	result := res.NewStruct()
 	if context.AddError(res.Decode(context.Context, result)); !context.HasError() {
-		context.AddError(res.CallSave(result, context.Context))
+		context.AddError(res.createRecord(result, context))
 	}
 
 	if context.HasError() {
 		responder.With("html", func() {
 			context.Writer.WriteHeader(HTTPUnprocessableEntity)
 			context.Execute("new", result)
-		}).With([]string{"json", "xml"}, func() {
+		}).With("json", func() {
+			context.writeProblemDetails(HTTPUnprocessableEntity, result)
+		}).With("xml", func() {
 			context.Writer.WriteHeader(HTTPUnprocessableEntity)
 			context.Encode("index", map[string]interface{}{"errors": context.GetErrors()})
 		}).Respond(context.Request)
 	} else {
+		context.publishLiveEvent(res, LiveEventCreated, fmt.Sprint(context.primaryKeyOf(result)))
+		context.triggerWebhooks(res, LiveEventCreated, result)
 		responder.With("html", func() {
 			context.Flash(string(context.t("qor_admin.form.successfully_created", "{{.Name}} was successfully created", res)), "success")
 			context.Writer.Header().Set("HX-Redirect", context.URLFor(result, res))
@@ -127,6 +290,7 @@ func (ac *Controller) Show(context *Context) {
 	res := context.Resource
 	context.Writer.Header().Set("HX-Push", context.URLFor(result, res))
 	context.AddError(err)
+	context.setVersionHeader(result)
 
 	responder.With("html", func() {
 		context.Execute("show", result)
@@ -143,6 +307,12 @@ func (ac *Controller) Edit(context *Context) {
 	}
 
 	context.AddError(err)
+	context.setVersionHeader(result)
+	if !context.HasError() {
+		_, _, err = context.acquireEditLock(false)
+		context.AddError(err)
+	}
+
 	responder.With("html", func() {
 		res := context.Resource
 		context.Writer.Header().Set("HX-Push", context.URLFor(result, res))
@@ -171,8 +341,32 @@ func (ac *Controller) Update(context *Context) {
 		originalDB := context.DB
 		tx := context.DB.Begin()
 		context.SetDB(tx)
+
+		context.AddError(context.checkEditLock())
+
+		// lock and check version of current record before decoding, as decoding reloads the record
+		current, versionErr := res.checkVersion(context)
+		previousVersion := res.versionValue(result)
+		if current != nil {
+			previousVersion = res.versionValue(current)
+		}
+
+		old := current
+		if old == nil {
+			old, err = res.findOldRecord(context.Context, HookBeforeUpdate, HookAfterUpdate)
+			context.AddError(err)
+		}
+
 		if context.AddError(res.Decode(context.Context, result)); !context.HasError() {
-			context.AddError(res.CallSave(result, context.Context))
+			if versionErr == ErrVersionConflict {
+				context.Set(VersionConflictKey, res.versionDiff(current, result, context))
+			}
+
+			if context.AddError(versionErr); !context.HasError() {
+				if context.AddError(res.bumpVersion(result, previousVersion)); !context.HasError() {
+					context.AddError(res.updateRecord(old, result, context, context.Context))
+				}
+			}
 		}
 		if context.HasError() {
 			tx.Rollback()
@@ -185,13 +379,36 @@ func (ac *Controller) Update(context *Context) {
 	context.Writer.Header().Set("HX-Push", context.URLFor(result, res))
 
 	if context.HasError() {
-		context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		status := HTTPUnprocessableEntity
+		for _, err := range context.GetErrors() {
+			switch err {
+			case ErrVersionConflict:
+				status = http.StatusConflict
+			case ErrVersionRequired:
+				status = http.StatusPreconditionRequired
+			case ErrEditLocked:
+				status = http.StatusLocked
+			}
+		}
+
 		responder.With("html", func() {
+			context.Writer.WriteHeader(status)
 			context.Execute("edit", result)
-		}).With([]string{"json", "xml"}, func() {
-			context.Encode("edit", map[string]interface{}{"errors": context.GetErrors()})
+		}).With("json", func() {
+			context.writeProblemDetails(status, result)
+		}).With("xml", func() {
+			context.Writer.WriteHeader(status)
+			errs := map[string]interface{}{"errors": context.GetErrors()}
+			if diffs := context.versionConflict(); diffs != nil {
+				errs["conflicts"] = diffs
+			}
+			context.Encode("edit", errs)
 		}).Respond(context.Request)
 	} else {
+		context.setVersionHeader(result)
+		context.AddError(context.releaseEditLock())
+		context.publishLiveEvent(res, LiveEventUpdated, context.ResourceID)
+		context.triggerWebhooks(res, LiveEventUpdated, result)
 		responder.With("html", func() {
 			context.Flash(string(context.t("qor_admin.form.successfully_updated", "{{.Name}} was successfully updated", res)), "success")
 			context.Execute("show", result)
@@ -206,19 +423,241 @@ func (ac *Controller) Delete(context *Context) {
 	res := context.Resource
 	status := http.StatusOK
 
-	if context.AddError(res.CallDelete(res.NewStruct(), context.Context)); context.HasError() {
+	if err := res.deleteRecords([]string{context.ResourceID}, context); err != nil {
+		context.AddError(err)
 		context.Flash(string(context.t("qor_admin.form.failed_to_delete", "Failed to delete {{.Name}}", res)), "error")
-		status = http.StatusNotFound
+
+		switch {
+		case errors.Is(err, ErrEditLocked):
+			status = http.StatusLocked
+		case errors.Is(err, ErrDeleteBlocked):
+			status = http.StatusConflict
+		case errors.Is(err, roles.ErrPermissionDenied):
+			status = http.StatusForbidden
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+		default:
+			status = HTTPUnprocessableEntity
+		}
 	}
 
 	responder.With("html", func() {
 		http.Redirect(context.Writer, context.Request, path.Join(ac.GetRouter().Prefix, res.ToParam()), http.StatusFound)
 	}).With([]string{"json", "xml"}, func() {
 		context.Writer.WriteHeader(status)
-		context.Encode("OK", map[string]interface{}{"status": "ok"})
+		if context.HasError() {
+			var errs []string
+			for _, err := range context.GetErrors() {
+				errs = append(errs, err.Error())
+			}
+			context.Encode("OK", map[string]interface{}{"errors": errs, "status": "error"})
+		} else {
+			context.Encode("OK", map[string]interface{}{"status": "ok"})
+		}
 	}).Respond(context.Request)
 }
 
+// DeletePreview show dependent records of records will be deleted, and what happens to them, records are the requested record or selected records with param primary_values[]
+func (ac *Controller) DeletePreview(context *Context) {
+	type dependency struct {
+		*DeleteDependency
+		Message string
+	}
+
+	var (
+		res           = context.Resource
+		primaryValues = context.Request.Form["primary_values[]"]
+		dependencies  = []dependency{}
+		messages      []string
+		blocked       bool
+	)
+
+	if context.ResourceID != "" {
+		primaryValues = append(primaryValues, context.ResourceID)
+	}
+
+	records, err := res.findDeletingRecords(primaryValues, context.Context)
+	if err == nil {
+		var results []*DeleteDependency
+		results, err = res.DeleteDependencies(records, context)
+		for _, result := range results {
+			message := context.deleteDependencyMessage(result)
+			blocked = blocked || result.Behavior == DeleteBlock
+			messages = append(messages, message)
+			dependencies = append(dependencies, dependency{DeleteDependency: result, Message: message})
+		}
+	}
+
+	context.Writer.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		json.NewEncoder(context.Writer).Encode(map[string]interface{}{"Errors": []string{err.Error()}})
+		return
+	}
+
+	json.NewEncoder(context.Writer).Encode(map[string]interface{}{
+		"Blocked":      blocked,
+		"Message":      strings.Join(messages, "\n"),
+		"Dependencies": dependencies,
+	})
+}
+
+// Merge render merge page of records selected with param primary_values[], or merge them into param survivor when POST, refer parseMergeChoices for params
+func (ac *Controller) Merge(context *Context) {
+	var (
+		res           = context.Resource
+		primaryValues = context.Request.Form["primary_values[]"]
+		view          = mergeView{PrimaryValues: primaryValues}
+		permitted     = res.canMerge(context)
+		survivor      interface{}
+		err           error
+	)
+
+	if !permitted {
+		context.AddError(roles.ErrPermissionDenied)
+	} else {
+		view.Metas = res.mergeMetas(context)
+		view.Survivor, view.Choices, err = parseMergeChoices(context.Request.Form, primaryValues, metaNames(view.Metas))
+		if context.AddError(err); !context.HasError() {
+			view.Records, err = res.findDeletingRecords(primaryValues, context.Context)
+			context.AddError(err)
+		}
+	}
+
+	if context.Request.Method == "POST" && !context.HasError() {
+		survivor, err = res.MergeRecords(view.Survivor, primaryValues, view.Choices, context)
+		context.AddError(err)
+	}
+
+	if !permitted {
+		context.Writer.WriteHeader(http.StatusForbidden)
+	} else if context.HasError() {
+		context.Writer.WriteHeader(HTTPUnprocessableEntity)
+	}
+
+	responder.With("html", func() {
+		if context.Request.Method == "POST" && !context.HasError() {
+			context.Flash(string(context.t("qor_admin.merge.successfully_merged", "{{.Name}} were successfully merged", res)), "success")
+			http.Redirect(context.Writer, context.Request, context.URLFor(survivor, res), http.StatusFound)
+			return
+		}
+		context.Execute("merge", view)
+	}).With("json", func() {
+		if context.HasError() {
+			context.Encode("edit", map[string]interface{}{"errors": context.GetErrors()})
+		} else if survivor != nil {
+			context.Encode("show", survivor)
+		} else {
+			context.Encode("index", view.Records)
+		}
+	}).Respond(context.Request)
+}
+
+// BulkEdit render bulk edit form of records selected with param primary_values[], or all records matching current filters if param all is true, apply chosen attributes of bulk_edit_fields[] to them when POST
+func (ac *Controller) BulkEdit(context *Context) {
+	var (
+		res        = context.Resource
+		metas      = res.bulkEditMetas(context)
+		fields     = parseBulkEditFields(context.Request.Form, metaNames(metas))
+		view       = bulkEditView{Query: bulkEditQuery(context, false), All: context.Request.Form.Get("all") == "true"}
+		metaValues *resource.MetaValues
+		err        error
+	)
+
+	if !res.canBulkEdit(context) {
+		context.AddError(roles.ErrPermissionDenied)
+	} else if context.Request.Method == "POST" {
+		view.PrimaryValues, err = res.bulkEditPrimaryValues(context, view.All)
+		view.Count = int64(len(view.PrimaryValues))
+		context.AddError(err)
+	} else {
+		view.PrimaryValues = context.Request.Form["primary_values[]"]
+		view.Count, err = res.bulkEditCount(context, view.All)
+		context.AddError(err)
+	}
+
+	if context.Request.Method == "POST" && !context.HasError() {
+		if len(fields) == 0 {
+			context.AddError(errors.New(string(context.t("qor_admin.bulk_edit.no_change", "Please choose attributes to change"))))
+		} else if metaValues, err = bulkEditMetaValues(context, metas, fields); err != nil {
+			context.AddError(err)
+		} else {
+			view.Results = res.BulkEditRecords(view.PrimaryValues, metaValues, context)
+			for _, result := range view.Results {
+				if len(result.Errors) > 0 {
+					view.Failed++
+				}
+			}
+		}
+	}
+
+	view.Record = res.bulkEditRecord(metaValues, context)
+	for _, meta := range metas {
+		view.Fields = append(view.Fields, BulkEditField{
+			Meta:     meta,
+			Sections: []*Section{{Resource: res, Rows: [][]string{{meta.Name}}}},
+			Changed:  isContainsColumn(fields, meta.Name),
+		})
+	}
+
+	if context.HasError() || view.Failed > 0 {
+		context.Writer.WriteHeader(HTTPUnprocessableEntity)
+	}
+
+	responder.With("html", func() {
+		if context.Request.Method == "POST" && !context.HasError() && view.Failed == 0 {
+			context.Flash(string(context.t("qor_admin.bulk_edit.successfully_updated", "{{.Count}} records were successfully updated", view)), "success")
+			http.Redirect(context.Writer, context.Request, context.URLFor(res), http.StatusFound)
+			return
+		}
+		context.Execute("bulk_edit", view)
+	}).With("json", func() {
+		var errs []string
+		for _, err := range context.GetErrors() {
+			errs = append(errs, err.Error())
+		}
+
+		context.Writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(context.Writer).Encode(map[string]interface{}{
+			"Count":   view.Count,
+			"Failed":  view.Failed,
+			"Results": view.Results,
+			"Errors":  errs,
+		})
+	}).Respond(context.Request)
+}
+
+// EditLock renew current user's edit lock of the record with POST, take over the lock with param take_over=true, release it with DELETE or param release=true, respond current lock as JSON
+func (ac *Controller) EditLock(context *Context) {
+	var (
+		holder   EditLock
+		acquired bool
+		err      error
+	)
+
+	if context.Request.Method == "DELETE" || context.Request.Form.Get("release") == "true" {
+		err = context.releaseEditLock()
+		acquired = true
+	} else {
+		holder, acquired, err = context.acquireEditLock(context.Request.Form.Get("take_over") == "true")
+	}
+
+	context.Writer.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		context.Writer.WriteHeader(HTTPUnprocessableEntity)
+		json.NewEncoder(context.Writer).Encode(map[string]interface{}{"Errors": []string{err.Error()}})
+		return
+	}
+
+	result := map[string]interface{}{"Locked": acquired}
+	if !acquired {
+		result["Holder"] = holder
+		result["Message"] = context.editLockMessage(&holder)
+		result["CanTakeOver"] = context.canTakeOverEditLock()
+	}
+	json.NewEncoder(context.Writer).Encode(result)
+}
+
 // Action handle action related requests
 func (ac *Controller) Action(context *Context) {
 	var action = ac.action
@@ -240,7 +679,14 @@ func (ac *Controller) Action(context *Context) {
 			actionArgument.Argument = result
 		}
 
-		context.AddError(action.Handler(&actionArgument))
+		if context.AddError(context.checkEditLocks(actionArgument.PrimaryValues...)); !context.HasError() {
+			context.AddError(context.Resource.executeAction(action, &actionArgument))
+		}
+
+		if !context.HasError() {
+			context.triggerActionWebhooks(action, actionArgument.PrimaryValues)
+			context.publishLiveEvent(context.Resource, LiveEventUpdated, context.unpublishedLiveEventIDs(actionArgument.PrimaryValues)...)
+		}
 
 		if !actionArgument.SkipDefaultResponse {
 			if !context.HasError() {
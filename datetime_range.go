@@ -0,0 +1,177 @@
+package admin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/now"
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/utils"
+)
+
+// DatetimeRange a time range includes Start, excludes End, a range with same Start and End is a moment
+type DatetimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsMoment return true if the range is a moment
+func (timeRange DatetimeRange) IsMoment() bool {
+	return timeRange.Start.Equal(timeRange.End)
+}
+
+// DatetimePreset a named time range relative to current time, e.g: today, last_7_days
+type DatetimePreset struct {
+	Name  string
+	Label string
+	Range func(current time.Time) DatetimeRange
+}
+
+// DatetimePresets presets could be used in datetime filters, search query, register your own with:
+//
+//	admin.DatetimePresets = append(admin.DatetimePresets, &admin.DatetimePreset{Name: "last_90_days", Label: "Last 90 Days", Range: ...})
+var DatetimePresets = []*DatetimePreset{
+	{Name: "today", Label: "Today", Range: func(current time.Time) DatetimeRange {
+		start := now.With(current).BeginningOfDay()
+		return DatetimeRange{Start: start, End: start.AddDate(0, 0, 1)}
+	}},
+	{Name: "yesterday", Label: "Yesterday", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfDay()
+		return DatetimeRange{Start: end.AddDate(0, 0, -1), End: end}
+	}},
+	{Name: "this_week", Label: "This Week", Range: func(current time.Time) DatetimeRange {
+		start := now.With(current).BeginningOfWeek()
+		return DatetimeRange{Start: start, End: start.AddDate(0, 0, 7)}
+	}},
+	{Name: "last_week", Label: "Last Week", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfWeek()
+		return DatetimeRange{Start: end.AddDate(0, 0, -7), End: end}
+	}},
+	{Name: "last_7_days", Label: "Last 7 Days", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfDay().AddDate(0, 0, 1)
+		return DatetimeRange{Start: end.AddDate(0, 0, -7), End: end}
+	}},
+	{Name: "last_30_days", Label: "Last 30 Days", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfDay().AddDate(0, 0, 1)
+		return DatetimeRange{Start: end.AddDate(0, 0, -30), End: end}
+	}},
+	{Name: "this_month", Label: "This Month", Range: func(current time.Time) DatetimeRange {
+		start := now.With(current).BeginningOfMonth()
+		return DatetimeRange{Start: start, End: start.AddDate(0, 1, 0)}
+	}},
+	{Name: "last_month", Label: "Last Month", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfMonth()
+		return DatetimeRange{Start: end.AddDate(0, -1, 0), End: end}
+	}},
+	{Name: "quarter_to_date", Label: "Quarter to Date", Range: func(current time.Time) DatetimeRange {
+		return DatetimeRange{Start: now.With(current).BeginningOfQuarter(), End: now.With(current).BeginningOfDay().AddDate(0, 0, 1)}
+	}},
+	{Name: "last_quarter", Label: "Last Quarter", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfQuarter()
+		return DatetimeRange{Start: end.AddDate(0, -3, 0), End: end}
+	}},
+	{Name: "year_to_date", Label: "Year to Date", Range: func(current time.Time) DatetimeRange {
+		return DatetimeRange{Start: now.With(current).BeginningOfYear(), End: now.With(current).BeginningOfDay().AddDate(0, 0, 1)}
+	}},
+	{Name: "last_year", Label: "Last Year", Range: func(current time.Time) DatetimeRange {
+		end := now.With(current).BeginningOfYear()
+		return DatetimeRange{Start: end.AddDate(-1, 0, 0), End: end}
+	}},
+}
+
+// GetDatetimePreset get datetime preset with name
+func GetDatetimePreset(name string) *DatetimePreset {
+	for _, preset := range DatetimePresets {
+		if preset.Name == name {
+			return preset
+		}
+	}
+	return nil
+}
+
+var (
+	relativeTimeRegexp = regexp.MustCompile(`^([+-]\d+)([hdwmy])$`)
+	yearRegexp         = regexp.MustCompile(`^\d{4}$`)
+	monthRegexp        = regexp.MustCompile(`^\d{4}-\d{1,2}$`)
+	dayRegexp          = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}$`)
+)
+
+// currentTime return current time in user's timezone, the timezone is detected with utils.ParseTime
+func currentTime(context *qor.Context) time.Time {
+	if reference, err := utils.ParseTime("2000-01-01 00:00:00", context); err == nil {
+		return time.Now().In(reference.Location())
+	}
+	return time.Now()
+}
+
+// ParseDatetimeRange parse time range from string in user's timezone, string could be:
+//
+//	today, last_7_days, quarter_to_date ... // preset, refer DatetimePresets
+//	-30d, -12h, +2w, -3m, -1y               // relative to now, the whole day if unit is day, week, month or year
+//	2024, 2024-03, 2024-03-05               // the whole year, month or day
+//	2024-03-05 10:30                        // a moment parsed with utils.ParseTime
+func ParseDatetimeRange(str string, context *qor.Context) (DatetimeRange, error) {
+	return parseDatetimeRange(str, currentTime(context), context)
+}
+
+func parseDatetimeRange(str string, current time.Time, context *qor.Context) (DatetimeRange, error) {
+	str = strings.TrimSpace(str)
+
+	if preset := GetDatetimePreset(str); preset != nil {
+		return preset.Range(current), nil
+	}
+
+	if matches := relativeTimeRegexp.FindStringSubmatch(str); len(matches) > 0 {
+		count, _ := strconv.Atoi(matches[1])
+
+		var day time.Time
+		switch matches[2] {
+		case "h":
+			moment := current.Add(time.Duration(count) * time.Hour)
+			return DatetimeRange{Start: moment, End: moment}, nil
+		case "d":
+			day = current.AddDate(0, 0, count)
+		case "w":
+			day = current.AddDate(0, 0, count*7)
+		case "m":
+			day = current.AddDate(0, count, 0)
+		case "y":
+			day = current.AddDate(count, 0, 0)
+		}
+
+		start := now.With(day).BeginningOfDay()
+		return DatetimeRange{Start: start, End: start.AddDate(0, 0, 1)}, nil
+	}
+
+	var (
+		timeStr             = str
+		years, months, days int
+		isPeriod            = true
+		parsedTime          time.Time
+		err                 error
+	)
+
+	switch {
+	case yearRegexp.MatchString(str):
+		timeStr, years = str+"-01-01", 1
+	case monthRegexp.MatchString(str):
+		timeStr, months = str+"-01", 1
+	case dayRegexp.MatchString(str):
+		days = 1
+	default:
+		isPeriod = false
+	}
+
+	if parsedTime, err = utils.ParseTime(timeStr, context); err != nil {
+		return DatetimeRange{}, fmt.Errorf("invalid time %v", str)
+	}
+
+	if isPeriod {
+		start := now.With(parsedTime).BeginningOfDay()
+		return DatetimeRange{Start: start, End: start.AddDate(years, months, days)}, nil
+	}
+	return DatetimeRange{Start: parsedTime, End: parsedTime}, nil
+}
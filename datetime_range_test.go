@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDatetimeRange(t *testing.T) {
+	var (
+		current = time.Date(2024, 5, 15, 10, 30, 0, 0, time.Local)
+		day     = func(year int, month time.Month, day int) time.Time {
+			return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+		}
+	)
+
+	cases := map[string]DatetimeRange{
+		"today":           {Start: day(2024, 5, 15), End: day(2024, 5, 16)},
+		"yesterday":       {Start: day(2024, 5, 14), End: day(2024, 5, 15)},
+		"last_7_days":     {Start: day(2024, 5, 9), End: day(2024, 5, 16)},
+		"this_month":      {Start: day(2024, 5, 1), End: day(2024, 6, 1)},
+		"quarter_to_date": {Start: day(2024, 4, 1), End: day(2024, 5, 16)},
+		"-30d":            {Start: day(2024, 4, 15), End: day(2024, 4, 16)},
+		"-2h":             {Start: current.Add(-2 * time.Hour), End: current.Add(-2 * time.Hour)},
+		"2024":            {Start: day(2024, 1, 1), End: day(2025, 1, 1)},
+		"2024-03":         {Start: day(2024, 3, 1), End: day(2024, 4, 1)},
+		"2024-03-05":      {Start: day(2024, 3, 5), End: day(2024, 3, 6)},
+	}
+
+	for str, expected := range cases {
+		timeRange, err := parseDatetimeRange(str, current, nil)
+		if err != nil {
+			t.Errorf("failed to parse %v, got %v", str, err)
+		} else if !timeRange.Start.Equal(expected.Start) || !timeRange.End.Equal(expected.End) {
+			t.Errorf("%v should be parsed as %v - %v, but got %v - %v", str, expected.Start, expected.End, timeRange.Start, timeRange.End)
+		}
+	}
+
+	if timeRange, err := parseDatetimeRange("2024-03-05 10:30", current, nil); err != nil || !timeRange.IsMoment() {
+		t.Errorf("time with clock should be parsed as a moment")
+	}
+
+	if _, err := parseDatetimeRange("next tuesday", current, nil); err == nil {
+		t.Errorf("invalid time should return error")
+	}
+}
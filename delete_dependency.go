@@ -0,0 +1,403 @@
+package admin
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DeleteBehavior what happens to dependent records when deleting a record
+type DeleteBehavior string
+
+const (
+	// DeleteKeep keep dependent records as they are
+	DeleteKeep DeleteBehavior = "keep"
+	// DeleteCascade delete dependent records, for many to many relations, only links in join table are deleted
+	DeleteCascade DeleteBehavior = "cascade"
+	// DeleteNullify set foreign keys of dependent records to NULL, for many to many relations, links in join table are deleted
+	DeleteNullify DeleteBehavior = "nullify"
+	// DeleteBlock refuse to delete records that have dependent records
+	DeleteBlock DeleteBehavior = "block"
+)
+
+// ErrDeleteBlocked returned when deleting records blocked by dependent records
+var ErrDeleteBlocked = errors.New("can't be deleted")
+
+// DefaultDeleteBehavior behavior of relations not configured with Resource.OnDeleteDependency
+var DefaultDeleteBehavior = DeleteKeep
+
+// DeleteDependency dependent records of has one, has many, many to many relations when deleting records
+type DeleteDependency struct {
+	Name     string
+	Type     string
+	Count    int64
+	Behavior DeleteBehavior
+	// URL index page of the relation's resource, blank if the relation isn't managed by admin
+	URL string `json:",omitempty"`
+
+	relationship *schema.Relationship
+}
+
+// OnDeleteDependency configure what happens to records of relation when deleting records of the resource, e.g:
+//
+//	user.OnDeleteDependency("Addresses", admin.DeleteCascade)
+//	user.OnDeleteDependency("Orders", admin.DeleteBlock)
+func (res *Resource) OnDeleteDependency(relation string, behavior DeleteBehavior) {
+	scope := utils.NewScope(res.Value)
+	if relationship, ok := scope.Relationships.Relations[relation]; !ok || relationship.Type == schema.BelongsTo {
+		utils.ExitWithMsg("%v isn't a has one, has many or many to many relation of resource %v", relation, res.Name)
+	}
+
+	if res.deleteBehaviors == nil {
+		res.deleteBehaviors = map[string]DeleteBehavior{}
+	}
+	res.deleteBehaviors[relation] = behavior
+}
+
+// deleteBehavior return configured behavior of relation
+func (res *Resource) deleteBehavior(relation string) DeleteBehavior {
+	if behavior, ok := res.deleteBehaviors[relation]; ok {
+		return behavior
+	}
+	return DefaultDeleteBehavior
+}
+
+// dependencyCondition return table and conditions of records depend on the records through relationship
+func dependencyCondition(relationship *schema.Relationship, records []interface{}) (string, map[string]interface{}) {
+	var (
+		table      = relationship.FieldSchema.Table
+		conditions = map[string]interface{}{}
+	)
+
+	if relationship.JoinTable != nil {
+		table = relationship.JoinTable.Table
+	}
+
+	for _, reference := range relationship.References {
+		if reference.OwnPrimaryKey {
+			var values []interface{}
+			for _, record := range records {
+				if value, zero := reference.PrimaryKey.ValueOf(stdcontext.Background(), reflect.Indirect(reflect.ValueOf(record))); !zero {
+					values = append(values, value)
+				}
+			}
+			conditions[fmt.Sprintf("%v.%v", table, reference.ForeignKey.DBName)] = values
+		} else if reference.PrimaryValue != "" {
+			conditions[fmt.Sprintf("%v.%v", table, reference.ForeignKey.DBName)] = reference.PrimaryValue
+		}
+	}
+	return table, conditions
+}
+
+// dependencyDB return db of records depend on the records through relationship
+func dependencyDB(db *gorm.DB, relationship *schema.Relationship, records []interface{}) *gorm.DB {
+	table, conditions := dependencyCondition(relationship, records)
+	if relationship.JoinTable != nil {
+		db = db.Table(table)
+	} else {
+		db = db.Model(reflect.New(relationship.FieldSchema.ModelType).Interface())
+	}
+
+	for column, value := range conditions {
+		if values, ok := value.([]interface{}); ok {
+			db = db.Where(fmt.Sprintf("%v IN ?", column), values)
+		} else {
+			db = db.Where(fmt.Sprintf("%v = ?", column), value)
+		}
+	}
+	return db
+}
+
+// DeleteDependencies count records depend on the records with has one, has many and many to many relations
+func (res *Resource) DeleteDependencies(records []interface{}, context *Context) ([]*DeleteDependency, error) {
+	return deleteDependencies(utils.NewScope(res.Value), res.deleteBehavior, records, context, false)
+}
+
+// deleteDependencies count records depend on the records of scope, count soft deleted records too if unscoped, which are purged with the records
+func deleteDependencies(scope *schema.Schema, behavior func(relation string) DeleteBehavior, records []interface{}, context *Context, unscoped bool) ([]*DeleteDependency, error) {
+	var (
+		dependencies []*DeleteDependency
+		db           = context.GetDB()
+	)
+
+	if len(records) == 0 {
+		return dependencies, nil
+	}
+
+	for _, group := range []struct {
+		Type          string
+		Relationships []*schema.Relationship
+	}{
+		{Type: "has_one", Relationships: scope.Relationships.HasOne},
+		{Type: "has_many", Relationships: scope.Relationships.HasMany},
+		{Type: "many_to_many", Relationships: scope.Relationships.Many2Many},
+	} {
+		for _, relationship := range group.Relationships {
+			dependency := &DeleteDependency{Name: relationship.Name, Type: group.Type, Behavior: behavior(relationship.Name), relationship: relationship}
+			tx := db.Session(&gorm.Session{NewDB: true})
+			if unscoped {
+				tx = tx.Unscoped()
+			}
+
+			if err := dependencyDB(tx, relationship, records).Count(&dependency.Count).Error; err != nil {
+				return dependencies, err
+			}
+
+			if dependency.Count == 0 {
+				continue
+			}
+
+			for _, r := range context.Admin.GetResources() {
+				if reflect.Indirect(reflect.ValueOf(r.Value)).Type() == relationship.FieldSchema.ModelType && r.HasPermission(roles.Read, context.Context) && !r.Config.Invisible {
+					dependency.URL = context.URLFor(r)
+					break
+				}
+			}
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies, nil
+}
+
+// deletedRecords records deleted with a resource in a transaction, live events and webhooks of them are sent after committing
+type deletedRecords struct {
+	resource *Resource
+	records  []interface{}
+}
+
+// applyDeleteDependencies cascade or nullify dependent records before deleting records, return error if any relation blocks the delete,
+// cascaded records are deleted with their resource's behaviors, hooks and search backend recursively, and collected into deleted
+func (res *Resource) applyDeleteDependencies(records []interface{}, context *Context, unscoped bool, deleted *[]deletedRecords) error {
+	dependencies, err := deleteDependencies(utils.NewScope(res.Value), res.deleteBehavior, records, context, unscoped)
+	if err != nil {
+		return err
+	}
+	return applyDependencies(res.Name, dependencies, records, context, unscoped, deleted)
+}
+
+// applyDependencies apply behaviors of dependencies of records
+func applyDependencies(name string, dependencies []*DeleteDependency, records []interface{}, context *Context, unscoped bool, deleted *[]deletedRecords) error {
+	for _, dependency := range dependencies {
+		if dependency.Behavior == DeleteBlock {
+			return fmt.Errorf("%v %w as %v %v depend on it", name, ErrDeleteBlocked, dependency.Count, dependency.Name)
+		}
+	}
+
+	db := context.GetDB().Session(&gorm.Session{NewDB: true})
+	if unscoped {
+		db = db.Unscoped()
+	}
+
+	var err error
+	for _, dependency := range dependencies {
+		var (
+			relationship = dependency.relationship
+			tx           = dependencyDB(db, relationship, records)
+		)
+
+		switch {
+		case dependency.Behavior == DeleteKeep:
+			continue
+		case relationship.JoinTable != nil:
+			err = tx.Delete(map[string]interface{}{}).Error
+		case dependency.Behavior == DeleteCascade:
+			err = context.cascadeDelete(relationship, tx, unscoped, deleted)
+		case dependency.Behavior == DeleteNullify:
+			values := map[string]interface{}{}
+			for _, reference := range relationship.References {
+				values[reference.ForeignKey.DBName] = nil
+			}
+			err = tx.UpdateColumns(values).Error
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cascadeDelete delete records of relationship found with tx, records of admin's resources are removed with the resource, others are removed with the relation model's default behaviors
+func (context *Context) cascadeDelete(relationship *schema.Relationship, tx *gorm.DB, unscoped bool, deleted *[]deletedRecords) error {
+	results := reflect.New(reflect.SliceOf(reflect.PtrTo(relationship.FieldSchema.ModelType)))
+	if err := tx.Find(results.Interface()).Error; err != nil {
+		return err
+	}
+
+	var records []interface{}
+	for i := 0; i < results.Elem().Len(); i++ {
+		records = append(records, results.Elem().Index(i).Interface())
+	}
+
+	if res := context.Admin.GetResource(relationship.FieldSchema.ModelType.String()); res != nil {
+		ctx := context.clone()
+		ctx.Resource = res
+		return res.removeRecords(records, nil, ctx, unscoped, deleted)
+	}
+
+	dependencies, err := deleteDependencies(relationship.FieldSchema, func(string) DeleteBehavior { return DefaultDeleteBehavior }, records, context, unscoped)
+	if err != nil {
+		return err
+	}
+
+	if err := applyDependencies(relationship.FieldSchema.Name, dependencies, records, context, unscoped, deleted); err != nil {
+		return err
+	}
+
+	db := context.GetDB().Session(&gorm.Session{NewDB: true})
+	if unscoped {
+		db = db.Unscoped()
+	}
+
+	for _, record := range records {
+		if err := db.Delete(record).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDependencyMessage describe what happens to dependent records
+func (context *Context) deleteDependencyMessage(dependency *DeleteDependency) string {
+	switch dependency.Behavior {
+	case DeleteCascade:
+		if dependency.Type == "many_to_many" {
+			return string(context.t("qor_admin.delete_dependency.unlink", "{{.Count}} {{.Name}} will be unlinked", dependency))
+		}
+		return string(context.t("qor_admin.delete_dependency.cascade", "{{.Count}} {{.Name}} will be deleted", dependency))
+	case DeleteNullify:
+		return string(context.t("qor_admin.delete_dependency.nullify", "{{.Count}} {{.Name}} will be unlinked", dependency))
+	case DeleteBlock:
+		return string(context.t("qor_admin.delete_dependency.block", "{{.Count}} {{.Name}} depend on it, it can't be deleted", dependency))
+	default:
+		return string(context.t("qor_admin.delete_dependency.keep", "{{.Count}} {{.Name}} will be kept", dependency))
+	}
+}
+
+// deletePreviewURL return url of delete preview for delete actions, blank for other actions
+func (context *Context) deletePreviewURL(action *Action, record interface{}, bulk bool) string {
+	res := context.Resource
+	if res == nil || (action.Name != "Delete" && action.Name != "Bulk Delete") {
+		return ""
+	}
+
+	if bulk || action.Name == "Bulk Delete" {
+		return path.Join(context.URLFor(res), "!delete_preview")
+	}
+	return path.Join(context.URLFor(record, res), "!delete_preview")
+}
+
+// findDeletingRecords find records will be deleted with primary values
+func (res *Resource) findDeletingRecords(primaryValues []string, context *qor.Context) ([]interface{}, error) {
+	var records []interface{}
+	for _, primaryValue := range primaryValues {
+		ctx := context.Clone()
+		ctx.ResourceID = primaryValue
+
+		record := res.NewStruct()
+		if err := res.CallFindOne(record, nil, ctx); err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// removeRecords delete records of the resource, dependent records are handled with configured behaviors before deleting, deleted records are collected into deleted,
+// records are deleted with CallDelete if primaryValues are given, or deleted with context's db, and purged permanently if unscoped,
+// purging only removes records already deleted, so hooks aren't called, and purged records aren't collected
+func (res *Resource) removeRecords(records []interface{}, primaryValues []string, context *Context, unscoped bool, deleted *[]deletedRecords) error {
+	tx := context.GetDB()
+	if !unscoped {
+		for _, record := range records {
+			if err := res.callHooks(HookBeforeDelete, &HookArgument{Context: context, Old: record, Tx: tx}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := res.applyDeleteDependencies(records, context, unscoped, deleted); err != nil {
+		return err
+	}
+
+	for idx, record := range records {
+		if len(primaryValues) > idx && !unscoped {
+			qorCtx := context.Context.Clone()
+			qorCtx.ResourceID = primaryValues[idx]
+			if err := res.CallDelete(res.NewStruct(), qorCtx); err != nil {
+				return err
+			}
+		} else {
+			db := tx.Session(&gorm.Session{NewDB: true})
+			if unscoped {
+				db = db.Unscoped()
+			}
+
+			if err := db.Delete(record).Error; err != nil {
+				return err
+			}
+
+			if res.searchBackend != nil {
+				if err := res.searchBackend.Unindex(record, res, context.Context); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !unscoped {
+			if err := res.callHooks(HookAfterDelete, &HookArgument{Context: context, Old: record, Tx: tx}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !unscoped && deleted != nil {
+		*deleted = append(*deleted, deletedRecords{resource: res, records: records})
+	}
+	return nil
+}
+
+// deleteRecords delete records with primary values in a transaction, dependent records are handled with configured behaviors before deleting
+func (res *Resource) deleteRecords(primaryValues []string, context *Context) error {
+	if err := context.checkEditLocks(primaryValues...); err != nil {
+		return err
+	}
+
+	var deleted []deletedRecords
+	err := context.GetDB().Transaction(func(tx *gorm.DB) error {
+		ctx := context.clone()
+		ctx.Context = context.Context.Clone()
+		ctx.SetDB(tx)
+
+		records, err := res.findDeletingRecords(primaryValues, ctx.Context)
+		if err != nil {
+			return err
+		}
+		return res.removeRecords(records, primaryValues, ctx, false, &deleted)
+	})
+
+	if err == nil {
+		context.publishDeletedRecords(deleted)
+	}
+	return err
+}
+
+// publishDeletedRecords publish live events and trigger webhooks of deleted records
+func (context *Context) publishDeletedRecords(deleted []deletedRecords) {
+	for _, d := range deleted {
+		var recordIDs []string
+		for _, record := range d.records {
+			recordIDs = append(recordIDs, fmt.Sprint(context.primaryKeyOf(record)))
+		}
+		context.publishLiveEvent(d.resource, LiveEventDeleted, recordIDs...)
+		context.triggerWebhooks(d.resource, LiveEventDeleted, d.records...)
+	}
+}
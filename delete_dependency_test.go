@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type deleteDependencyUser struct {
+	gorm.Model
+	Addresses []deleteDependencyAddress
+	Languages []deleteDependencyLanguage `gorm:"many2many:delete_dependency_user_languages;"`
+}
+
+type deleteDependencyAddress struct {
+	gorm.Model
+	DeleteDependencyUserID uint
+}
+
+type deleteDependencyLanguage struct {
+	gorm.Model
+}
+
+func TestDependencyCondition(t *testing.T) {
+	userSchema, err := schema.Parse(&deleteDependencyUser{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []interface{}{
+		&deleteDependencyUser{Model: gorm.Model{ID: 1}},
+		&deleteDependencyUser{Model: gorm.Model{ID: 2}},
+	}
+
+	table, conditions := dependencyCondition(userSchema.Relationships.Relations["Addresses"], records)
+	if table != "delete_dependency_addresses" || !reflect.DeepEqual(conditions, map[string]interface{}{
+		"delete_dependency_addresses.delete_dependency_user_id": []interface{}{uint(1), uint(2)},
+	}) {
+		t.Errorf("wrong condition of has many relation, got %v %v", table, conditions)
+	}
+
+	table, conditions = dependencyCondition(userSchema.Relationships.Relations["Languages"], records)
+	if table != "delete_dependency_user_languages" || !reflect.DeepEqual(conditions, map[string]interface{}{
+		"delete_dependency_user_languages.delete_dependency_user_id": []interface{}{uint(1), uint(2)},
+	}) {
+		t.Errorf("wrong condition of many to many relation, got %v %v", table, conditions)
+	}
+}
@@ -0,0 +1,134 @@
+package admin
+
+import (
+	stdcontext "context"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DuplicateParam param of new page, which is primary value of the record to duplicate
+const DuplicateParam = "duplicate"
+
+// registerDuplicateAction register built-in action Duplicate, which opens new page filled with a copy of the record
+func (res *Resource) registerDuplicateAction() {
+	res.Action(&Action{
+		Name:        "Duplicate",
+		Method:      "GET",
+		URLOpenType: "slideout",
+		Permission:  res.Config.Permission,
+		Modes:       []string{"menu_item", "edit", "show"},
+		URL: func(record interface{}, context *Context) string {
+			return path.Join(context.URLFor(res), "new") + "?" + url.Values{DuplicateParam: {fmt.Sprint(context.primaryKeyOf(record))}}.Encode()
+		},
+		Visible: func(record interface{}, context *Context) bool {
+			return record != nil && res.HasPermission(roles.Create, context.Context)
+		},
+	})
+}
+
+// Duplicate find the record with primary value, and return a copy of it to fill new form, only attributes of NewAttrs are copied, primary keys, unique fields and timestamps are cleared, single_edit, collection_edit records are deep copied, the copy could be adjusted with DuplicateHandler
+func (res *Resource) Duplicate(primaryValue string, context *Context) (interface{}, error) {
+	var (
+		original = res.NewStruct()
+		qorCtx   = context.Context.Clone()
+		db       = qorCtx.GetDB()
+	)
+
+	for _, preload := range res.duplicatePreloads(context) {
+		db = db.Preload(preload)
+	}
+	qorCtx.SetDB(db)
+	qorCtx.ResourceID = primaryValue
+
+	if err := res.CallFindOne(original, nil, qorCtx); err != nil {
+		return nil, err
+	}
+
+	duplicate := res.duplicateRecord(original, context)
+	if res.DuplicateHandler != nil {
+		if err := res.DuplicateHandler(original, duplicate, context); err != nil {
+			return nil, err
+		}
+	}
+	return duplicate, nil
+}
+
+// duplicateMetas metas of NewAttrs current user could create
+func (res *Resource) duplicateMetas(context *Context) []*Meta {
+	return res.ConvertSectionToMetas(res.allowedSections(res.NewAttrs(), context, roles.Create))
+}
+
+// duplicatePreloads return relations need to be preloaded to deep copy single_edit, collection_edit records, e.g: Addresses, Profile.Phones
+func (res *Resource) duplicatePreloads(context *Context) (preloads []string) {
+	for _, meta := range res.duplicateMetas(context) {
+		if (meta.Type == "single_edit" || meta.Type == "collection_edit") && meta.Resource != nil && meta.FieldName != "" {
+			preloads = append(preloads, meta.FieldName)
+			for _, preload := range meta.Resource.duplicatePreloads(context) {
+				preloads = append(preloads, meta.FieldName+"."+preload)
+			}
+		}
+	}
+	return
+}
+
+// isDuplicateClearedField primary keys, unique fields and timestamps are not copied
+func isDuplicateClearedField(field *schema.Field) bool {
+	return field.PrimaryKey || field.Unique || field.AutoCreateTime != 0 || field.AutoUpdateTime != 0 ||
+		field.FieldType == reflect.TypeOf(gorm.DeletedAt{})
+}
+
+// duplicateRecord copy record with resource's metas of NewAttrs
+func (res *Resource) duplicateRecord(original interface{}, context *Context) interface{} {
+	var (
+		scope     = utils.NewScope(res.Value)
+		duplicate = res.NewStruct()
+		src       = reflect.Indirect(reflect.ValueOf(original))
+		dst       = reflect.Indirect(reflect.ValueOf(duplicate))
+		ctx       = stdcontext.Background()
+	)
+
+	for _, meta := range res.duplicateMetas(context) {
+		field := scope.LookUpField(meta.FieldName)
+		if field == nil || isDuplicateClearedField(field) {
+			continue
+		}
+
+		value := field.ReflectValueOf(ctx, src)
+		if !value.IsValid() {
+			continue
+		}
+
+		target := field.ReflectValueOf(ctx, dst)
+		switch {
+		case meta.Type == "single_edit" && meta.Resource != nil:
+			if value.Kind() == reflect.Ptr {
+				if !value.IsNil() {
+					target.Set(reflect.ValueOf(meta.Resource.duplicateRecord(value.Interface(), context)))
+				}
+			} else {
+				target.Set(reflect.ValueOf(meta.Resource.duplicateRecord(value.Addr().Interface(), context)).Elem())
+			}
+		case meta.Type == "collection_edit" && meta.Resource != nil:
+			records := reflect.MakeSlice(value.Type(), 0, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				elem := value.Index(i)
+				if elem.Kind() == reflect.Ptr {
+					records = reflect.Append(records, reflect.ValueOf(meta.Resource.duplicateRecord(elem.Interface(), context)))
+				} else {
+					records = reflect.Append(records, reflect.ValueOf(meta.Resource.duplicateRecord(elem.Addr().Interface(), context)).Elem())
+				}
+			}
+			target.Set(records)
+		default:
+			target.Set(value)
+		}
+	}
+	return duplicate
+}
@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type duplicateProduct struct {
+	gorm.Model
+	Code string `gorm:"unique"`
+	Name string
+}
+
+func TestIsDuplicateClearedField(t *testing.T) {
+	productSchema, err := schema.Parse(&duplicateProduct{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cleared := range map[string]bool{
+		"ID":        true,
+		"CreatedAt": true,
+		"UpdatedAt": true,
+		"DeletedAt": true,
+		"Code":      true,
+		"Name":      false,
+	} {
+		if isDuplicateClearedField(productSchema.LookUpField(name)) != cleared {
+			t.Errorf("field %v should be cleared when duplicating: %v", name, cleared)
+		}
+	}
+}
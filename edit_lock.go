@@ -0,0 +1,335 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EditLockKey context key of the lock held by another user when opening edit page
+const EditLockKey = "edit_lock"
+
+// ErrEditLocked update is refused as the record is being edited by another user
+var ErrEditLocked = errors.New("record is being edited by another user")
+
+// EditLock lease of a record being edited by a user
+type EditLock struct {
+	Resource  string
+	RecordID  string
+	UserID    string
+	UserName  string
+	ExpiresAt time.Time
+}
+
+// Expired check if the lease is expired
+func (lock EditLock) Expired() bool {
+	return !lock.ExpiresAt.After(time.Now())
+}
+
+// EditLockStore store of edit locks, refer DBEditLockStore, MemoryEditLockStore
+type EditLockStore interface {
+	// Acquire acquire or renew the lock for its user, return current holder and false if the lock is held by another user, takeOver acquires the lock anyway
+	Acquire(lock EditLock, takeOver bool) (holder EditLock, acquired bool, err error)
+	// Get return unexpired lock of the record
+	Get(resource, recordID string) (lock EditLock, found bool, err error)
+	// Release release the lock if it is held by the user
+	Release(resource, recordID, userID string) error
+}
+
+// EditLockConfig edit lock configuration of a resource, refer Resource.UseEditLock
+type EditLockConfig struct {
+	// Store store of locks, default is a DBEditLockStore with admin's DB
+	Store EditLockStore
+	// TTL lease duration of locks, locks are renewed by heartbeats while edit page is open, default is 2 minutes
+	TTL time.Duration
+	// Enforce refuse updates from users don't hold the lock, by default the lock only shows who is editing the record
+	Enforce bool
+	// TakeOverPermission permission to take over locks held by others, it is checked with roles.Update, default is admin only
+	TakeOverPermission *roles.Permission
+}
+
+// UseEditLock show who is editing a record on its edit page, and refuse updates from others if Enforce is true, e.g:
+//
+//	order.UseEditLock(&admin.EditLockConfig{Enforce: true})
+//
+// Opening edit page acquires a lock of the record, which is renewed by heartbeats until the page is closed
+func (res *Resource) UseEditLock(config *EditLockConfig) {
+	if config == nil {
+		config = &EditLockConfig{}
+	}
+
+	if config.Store == nil {
+		db := res.GetAdmin().DB
+		if err := db.AutoMigrate(&QorAdminEditLock{}); err != nil {
+			utils.ExitWithMsg(err)
+		}
+		config.Store = &DBEditLockStore{DB: db}
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = 2 * time.Minute
+	}
+
+	if config.TakeOverPermission == nil {
+		config.TakeOverPermission = roles.Allow(roles.Update, "admin")
+	}
+	res.editLock = config
+
+	controller := &Controller{Admin: res.GetAdmin()}
+	lockPath := "/!edit_lock"
+	if !res.Config.Singleton {
+		lockPath = path.Join(res.ParamIDName(), "!edit_lock")
+	}
+	res.RegisterRoute("POST", lockPath, controller.EditLock, &RouteConfig{PermissionMode: roles.Update})
+	res.RegisterRoute("DELETE", lockPath, controller.EditLock, &RouteConfig{PermissionMode: roles.Update})
+}
+
+// newEditLock return a lock of current record for current user, false if edit lock isn't used or there is no current user
+func (context *Context) newEditLock() (EditLock, bool) {
+	res := context.Resource
+	if res == nil || res.editLock == nil || context.CurrentUser == nil {
+		return EditLock{}, false
+	}
+
+	return EditLock{
+		Resource:  res.ToParam(),
+		RecordID:  context.ResourceID,
+		UserID:    fmt.Sprint(context.CurrentUser.GetID()),
+		UserName:  context.CurrentUser.DisplayName(),
+		ExpiresAt: time.Now().Add(res.editLock.TTL),
+	}, true
+}
+
+// acquireEditLock acquire or renew lock of current record, the lock held by another user is saved in context with EditLockKey
+func (context *Context) acquireEditLock(takeOver bool) (EditLock, bool, error) {
+	lock, ok := context.newEditLock()
+	if !ok {
+		return lock, true, nil
+	}
+
+	if takeOver && !context.canTakeOverEditLock() {
+		return lock, false, roles.ErrPermissionDenied
+	}
+
+	holder, acquired, err := context.Resource.editLock.Store.Acquire(lock, takeOver)
+	if err == nil && !acquired {
+		context.Set(EditLockKey, holder)
+	}
+	return holder, acquired, err
+}
+
+// checkEditLock return ErrEditLocked if the lock is enforced and current record is locked by another user
+func (context *Context) checkEditLock() error {
+	return context.checkEditLocks(context.ResourceID)
+}
+
+// checkEditLocks return ErrEditLocked if the lock is enforced and any of current resource's records is locked by another user, e.g: records changed by bulk edit, merge and actions
+func (context *Context) checkEditLocks(recordIDs ...string) error {
+	lock, ok := context.newEditLock()
+	if !ok || !context.Resource.editLock.Enforce {
+		return nil
+	}
+
+	for _, recordID := range recordIDs {
+		holder, found, err := context.Resource.editLock.Store.Get(lock.Resource, recordID)
+		if err != nil {
+			return err
+		}
+
+		if found && holder.UserID != lock.UserID {
+			context.Set(EditLockKey, holder)
+			return ErrEditLocked
+		}
+	}
+	return nil
+}
+
+// releaseEditLock release current user's lock of current record
+func (context *Context) releaseEditLock() error {
+	if lock, ok := context.newEditLock(); ok {
+		return context.Resource.editLock.Store.Release(lock.Resource, lock.RecordID, lock.UserID)
+	}
+	return nil
+}
+
+// canTakeOverEditLock check if current user could take over locks held by others
+func (context *Context) canTakeOverEditLock() bool {
+	res := context.Resource
+	if res == nil || res.editLock == nil {
+		return false
+	}
+
+	var userRoles []interface{}
+	for _, role := range context.Roles {
+		userRoles = append(userRoles, role)
+	}
+	return res.editLock.TakeOverPermission.HasPermission(roles.Update, userRoles...)
+}
+
+// editLockHolder return the lock held by another user for templates
+func (context *Context) editLockHolder() *EditLock {
+	if lock, ok := context.Get(EditLockKey).(EditLock); ok {
+		return &lock
+	}
+	return nil
+}
+
+// editLockURL return url of heartbeats of the record's edit lock, blank if edit lock isn't used
+func (context *Context) editLockURL(record interface{}) string {
+	res := context.Resource
+	if res == nil || res.editLock == nil || context.CurrentUser == nil {
+		return ""
+	}
+
+	if res.Config.Singleton {
+		return path.Join(context.URLFor(res), "!edit_lock")
+	}
+	return path.Join(context.URLFor(record, res), "!edit_lock")
+}
+
+// editLockInterval return interval of heartbeats in milliseconds, locks are renewed 3 times in a lease
+func (context *Context) editLockInterval() int64 {
+	if res := context.Resource; res != nil && res.editLock != nil {
+		return (res.editLock.TTL / 3).Milliseconds()
+	}
+	return 0
+}
+
+// editLockMessage describe who is holding the lock
+func (context *Context) editLockMessage(lock *EditLock) string {
+	return string(context.t("qor_admin.edit_lock.editing", "{{.UserName}} is editing this record", lock))
+}
+
+// QorAdminEditLock edit locks saved by DBEditLockStore
+type QorAdminEditLock struct {
+	Resource  string `gorm:"primaryKey;size:128"`
+	RecordID  string `gorm:"primaryKey;size:128"`
+	UserID    string
+	UserName  string
+	ExpiresAt time.Time
+}
+
+func (lock QorAdminEditLock) toEditLock() EditLock {
+	return EditLock{Resource: lock.Resource, RecordID: lock.RecordID, UserID: lock.UserID, UserName: lock.UserName, ExpiresAt: lock.ExpiresAt}
+}
+
+// DBEditLockStore save edit locks in database, so they are shared by all processes
+type DBEditLockStore struct {
+	DB *gorm.DB
+}
+
+// Acquire acquire or renew the lock for its user
+func (store *DBEditLockStore) Acquire(lock EditLock, takeOver bool) (holder EditLock, acquired bool, err error) {
+	err = store.DB.Transaction(func(tx *gorm.DB) error {
+		var (
+			current QorAdminEditLock
+			row     = QorAdminEditLock{Resource: lock.Resource, RecordID: lock.RecordID, UserID: lock.UserID, UserName: lock.UserName, ExpiresAt: lock.ExpiresAt}
+			where   = tx.Where("resource = ? AND record_id = ?", lock.Resource, lock.RecordID).Session(&gorm.Session{})
+		)
+
+		err := where.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+			if result.Error != nil || result.RowsAffected > 0 {
+				holder, acquired = lock, result.Error == nil
+				return result.Error
+			}
+
+			// created by another request at the same time
+			if err := where.Take(&current).Error; err != nil {
+				return err
+			}
+			holder = current.toEditLock()
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if holder = current.toEditLock(); !takeOver && holder.UserID != lock.UserID && !holder.Expired() {
+			return nil
+		}
+
+		holder, acquired = lock, true
+		return where.Model(&QorAdminEditLock{}).Updates(map[string]interface{}{
+			"user_id":    lock.UserID,
+			"user_name":  lock.UserName,
+			"expires_at": lock.ExpiresAt,
+		}).Error
+	})
+	return holder, acquired, err
+}
+
+// Get return unexpired lock of the record
+func (store *DBEditLockStore) Get(resource, recordID string) (EditLock, bool, error) {
+	var current QorAdminEditLock
+	err := store.DB.Where("resource = ? AND record_id = ? AND expires_at > ?", resource, recordID, time.Now()).Take(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return EditLock{}, false, nil
+	}
+	return current.toEditLock(), err == nil, err
+}
+
+// Release release the lock if it is held by the user
+func (store *DBEditLockStore) Release(resource, recordID, userID string) error {
+	return store.DB.Where("resource = ? AND record_id = ? AND user_id = ?", resource, recordID, userID).Delete(&QorAdminEditLock{}).Error
+}
+
+// MemoryEditLockStore save edit locks in memory, useful for tests and single process deployments
+type MemoryEditLockStore struct {
+	mutex sync.Mutex
+	locks map[string]EditLock
+}
+
+// NewMemoryEditLockStore new memory edit lock store
+func NewMemoryEditLockStore() *MemoryEditLockStore {
+	return &MemoryEditLockStore{locks: map[string]EditLock{}}
+}
+
+func (store *MemoryEditLockStore) key(resource, recordID string) string {
+	return resource + "/" + recordID
+}
+
+// Acquire acquire or renew the lock for its user
+func (store *MemoryEditLockStore) Acquire(lock EditLock, takeOver bool) (EditLock, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	key := store.key(lock.Resource, lock.RecordID)
+	if current, ok := store.locks[key]; ok && !takeOver && current.UserID != lock.UserID && !current.Expired() {
+		return current, false, nil
+	}
+
+	store.locks[key] = lock
+	return lock, true, nil
+}
+
+// Get return unexpired lock of the record
+func (store *MemoryEditLockStore) Get(resource, recordID string) (EditLock, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	lock, ok := store.locks[store.key(resource, recordID)]
+	if !ok || lock.Expired() {
+		return EditLock{}, false, nil
+	}
+	return lock, true, nil
+}
+
+// Release release the lock if it is held by the user
+func (store *MemoryEditLockStore) Release(resource, recordID, userID string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	key := store.key(resource, recordID)
+	if lock, ok := store.locks[key]; ok && lock.UserID == userID {
+		delete(store.locks, key)
+	}
+	return nil
+}
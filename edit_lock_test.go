@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryEditLockStore(t *testing.T) {
+	var (
+		store  = NewMemoryEditLockStore()
+		jinzhu = EditLock{Resource: "orders", RecordID: "1", UserID: "1", UserName: "jinzhu", ExpiresAt: time.Now().Add(time.Minute)}
+		alice  = EditLock{Resource: "orders", RecordID: "1", UserID: "2", UserName: "alice", ExpiresAt: time.Now().Add(time.Minute)}
+	)
+
+	if _, acquired, _ := store.Acquire(jinzhu, false); !acquired {
+		t.Errorf("should acquire lock of a record not being edited")
+	}
+
+	if holder, acquired, _ := store.Acquire(alice, false); acquired || holder.UserName != "jinzhu" {
+		t.Errorf("should not acquire lock held by others, got %v %v", acquired, holder)
+	}
+
+	if _, acquired, _ := store.Acquire(jinzhu, false); !acquired {
+		t.Errorf("holder should renew its lock")
+	}
+
+	if _, acquired, _ := store.Acquire(alice, true); !acquired {
+		t.Errorf("should take over lock held by others")
+	}
+
+	store.Release("orders", "1", "1")
+	if holder, found, _ := store.Get("orders", "1"); !found || holder.UserName != "alice" {
+		t.Errorf("lock should only be released by its holder, got %v %v", found, holder)
+	}
+
+	alice.ExpiresAt = time.Now().Add(-time.Second)
+	store.Acquire(alice, false)
+	if _, found, _ := store.Get("orders", "1"); found {
+		t.Errorf("expired lock should not be found")
+	}
+
+	if _, acquired, _ := store.Acquire(jinzhu, false); !acquired {
+		t.Errorf("should acquire expired lock")
+	}
+}
@@ -0,0 +1,279 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simonedbarber/qor/resource"
+	"github.com/simonedbarber/qor/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// FacetCountTTL how long facet counts of scopes and filters are cached, counts are cached by their queries
+var FacetCountTTL = 30 * time.Second
+
+// facet a facet of current search, counts of a facet are computed against current search without the facet's own scopes or filter
+type facet struct {
+	Scopes []*Scope
+	Filter *Filter
+}
+
+func (f *facet) excludes(scope *Scope) bool {
+	for _, s := range f.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type facetCountCacheItem struct {
+	Counts    map[string]int64
+	ExpiredAt time.Time
+}
+
+// facetCountCache cache facet counts by query
+var facetCountCache = struct {
+	sync.Mutex
+	items map[string]facetCountCacheItem
+}{items: map[string]facetCountCacheItem{}}
+
+// cachedFacetCounts return cached counts of the query, or count and cache them
+func cachedFacetCounts(key string, count func() (map[string]int64, error)) (map[string]int64, error) {
+	facetCountCache.Lock()
+	item, ok := facetCountCache.items[key]
+	facetCountCache.Unlock()
+
+	if ok && time.Now().Before(item.ExpiredAt) {
+		return item.Counts, nil
+	}
+
+	counts, err := count()
+	if err != nil {
+		return nil, err
+	}
+
+	facetCountCache.Lock()
+	defer facetCountCache.Unlock()
+	for k, item := range facetCountCache.items {
+		if time.Now().After(item.ExpiredAt) {
+			delete(facetCountCache.items, k)
+		}
+	}
+	facetCountCache.items[key] = facetCountCacheItem{Counts: counts, ExpiredAt: time.Now().Add(FacetCountTTL)}
+	return counts, nil
+}
+
+// formatCount format count with digit group separators, e.g: 1,204
+func formatCount(count int64) string {
+	if count < 0 {
+		return "-" + formatCount(-count)
+	}
+
+	str := strconv.FormatInt(count, 10)
+
+	for idx := len(str) - 3; idx > 0; idx -= 3 {
+		str = str[:idx] + "," + str[idx:]
+	}
+	return str
+}
+
+// facetValueString convert value scanned from database to string, which is used to match collection values of filters
+func facetValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// facetDB return db of current search without the facet's own scopes, filter, sorting and pagination
+func (s *Searcher) facetDB(f *facet) *gorm.DB {
+	var (
+		searcher = s.clone()
+		context  = s.Context.Context.Clone()
+	)
+
+	if context.Request != nil {
+		searcher = searcher.parseRequest(context)
+	}
+	searcher.facet = f
+
+	var scopes []*Scope
+	for _, scope := range searcher.scopes {
+		if !f.excludes(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	searcher.scopes = scopes
+
+	if f.Filter != nil && searcher.filters != nil {
+		filters := map[*Filter]*resource.MetaValues{}
+		for filter, values := range searcher.filters {
+			if filter != f.Filter {
+				filters[filter] = values
+			}
+		}
+		searcher.filters = filters
+	}
+
+	searcher.filterData(context, true)
+	return context.GetDB().Model(s.Resource.Value)
+}
+
+// scopeCounts count records of scopes in a scope menu with one query, keyed by scope name
+func (s *Searcher) scopeCounts(scopes []*Scope) (map[string]int64, error) {
+	var (
+		db      = s.facetDB(&facet{Scopes: scopes})
+		columns []string
+		exprs   []interface{}
+		names   []string
+		counts  = map[string]int64{}
+	)
+
+	for _, scope := range scopes {
+		// run scope handler with a new db to get its conditions, scopes with joins are counted separately as joins change the count
+		tx := scope.Handler(db.Session(&gorm.Session{NewDB: true}), s.Context.Context)
+		if len(tx.Statement.Joins) > 0 {
+			var count int64
+			if err := scope.Handler(db.Session(&gorm.Session{}), s.Context.Context).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			counts[scope.Name] = count
+			continue
+		}
+
+		var expr clause.Expression = clause.Expr{SQL: "1 = 1"}
+		if where, ok := tx.Statement.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			expr = parenthesesExpression{clause.AndConditions{Exprs: where.Exprs}}
+		}
+
+		columns = append(columns, fmt.Sprintf("COUNT(CASE WHEN ? THEN 1 END) AS facet_%v", len(columns)))
+		exprs = append(exprs, expr)
+		names = append(names, scope.Name)
+	}
+
+	if len(columns) == 0 {
+		return counts, nil
+	}
+
+	query := db.Session(&gorm.Session{}).Select(strings.Join(columns, ", "), exprs...)
+	key := "scopes:" + db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Select(strings.Join(columns, ", "), exprs...).Find(&[]map[string]interface{}{})
+	})
+
+	results, err := cachedFacetCounts(key, func() (map[string]int64, error) {
+		values := make([]int64, len(columns))
+		dests := make([]interface{}, len(columns))
+		for idx := range values {
+			dests[idx] = &values[idx]
+		}
+
+		if err := query.Row().Scan(dests...); err != nil {
+			return nil, err
+		}
+
+		results := map[string]int64{}
+		for idx, name := range names {
+			results[name] = values[idx]
+		}
+		return results, nil
+	})
+
+	for name, count := range results {
+		counts[name] = count
+	}
+	return counts, err
+}
+
+// facetColumn return column of filter to group by, only filters of columns or belongs_to relations could be counted
+func (res *Resource) facetColumn(filter *Filter) (string, bool) {
+	scope := utils.NewScope(res.Value)
+	field := scope.LookUpField(filter.Name)
+	if field == nil {
+		return "", false
+	}
+
+	if relationship := scope.Relationships.Relations[field.Name]; relationship != nil {
+		if relationship.Type != schema.BelongsTo || len(relationship.References) != 1 {
+			return "", false
+		}
+		field = relationship.References[0].ForeignKey
+	}
+
+	if field.DBName == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%v.%v", scope.Table, field.DBName), true
+}
+
+// filterCounts count records of filter's values with one GROUP BY query, keyed by value
+func (s *Searcher) filterCounts(filter *Filter) (map[string]int64, error) {
+	column, ok := s.Resource.facetColumn(filter)
+	if !ok {
+		return nil, fmt.Errorf("filter %v couldn't be counted", filter.Name)
+	}
+
+	var (
+		db        = s.facetDB(&facet{Filter: filter})
+		selectSQL = fmt.Sprintf("%v, COUNT(*)", column)
+		query     = db.Session(&gorm.Session{}).Select(selectSQL).Group(column)
+	)
+
+	key := "filter:" + db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Select(selectSQL).Group(column).Find(&[]map[string]interface{}{})
+	})
+
+	return cachedFacetCounts(key, func() (map[string]int64, error) {
+		rows, err := query.Rows()
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		counts := map[string]int64{}
+		for rows.Next() {
+			var (
+				value interface{}
+				count int64
+			)
+
+			if err := rows.Scan(&value, &count); err != nil {
+				return nil, err
+			}
+			counts[facetValueString(value)] += count
+		}
+		return counts, rows.Err()
+	})
+}
+
+// filterOptionCount return formatted count of filter's option, return blank if the filter doesn't show counts
+func (context *Context) filterOptionCount(filter *Filter, value interface{}) string {
+	if !filter.ShowCount || context.Searcher == nil {
+		return ""
+	}
+
+	if context.Searcher.facetCounts == nil {
+		context.Searcher.facetCounts = map[*Filter]map[string]int64{}
+	}
+
+	counts, ok := context.Searcher.facetCounts[filter]
+	if !ok {
+		// counts are left blank if failed to count, the filter still works
+		counts, _ = context.Searcher.filterCounts(filter)
+		context.Searcher.facetCounts[filter] = counts
+	}
+
+	if counts == nil {
+		return ""
+	}
+	return formatCount(counts[facetValueString(value)])
+}
@@ -0,0 +1,33 @@
+package admin
+
+import "testing"
+
+func TestFormatCount(t *testing.T) {
+	for count, str := range map[int64]string{
+		0:        "0",
+		12:       "12",
+		999:      "999",
+		1204:     "1,204",
+		123456:   "123,456",
+		1234567:  "1,234,567",
+		-1234567: "-1,234,567",
+	} {
+		if result := formatCount(count); result != str {
+			t.Errorf("format count %v: expect %v, got %v", count, str, result)
+		}
+	}
+}
+
+func TestFacetValueString(t *testing.T) {
+	if result := facetValueString([]byte("paid")); result != "paid" {
+		t.Errorf("expect paid, got %v", result)
+	}
+
+	if result := facetValueString(int64(12)); result != "12" {
+		t.Errorf("expect 12, got %v", result)
+	}
+
+	if result := facetValueString(nil); result != "" {
+		t.Errorf("expect blank, got %v", result)
+	}
+}
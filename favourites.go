@@ -0,0 +1,214 @@
+package admin
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/simonedbarber/roles"
+)
+
+// FavouritesKey settings key used to save user's favourite menus and saved filters
+const FavouritesKey = "favourites"
+
+// FavouritesMenuName name of the sidebar group that lists user's favourites
+var FavouritesMenuName = "Favourites"
+
+// Favourite a menu or a saved filter pinned to the top of sidebar, saved with SettingsStorage per user
+type Favourite struct {
+	// Menu names of a menu with its ancestors, e.g: []string{"Product Management", "Products"}
+	Menu []string `json:",omitempty"`
+	// Resource, SavedFilter param of the resource and name of the saved filter
+	Resource    string `json:",omitempty"`
+	SavedFilter string `json:",omitempty"`
+}
+
+// Key return identity of the favourite, used in forms, e.g: `menu:Product%20Management/Products`, `saved_filter:orders/Pending`
+func (favourite Favourite) Key() string {
+	if favourite.SavedFilter != "" {
+		return "saved_filter:" + url.PathEscape(favourite.Resource) + "/" + url.PathEscape(favourite.SavedFilter)
+	}
+
+	var names []string
+	for _, name := range favourite.Menu {
+		names = append(names, url.PathEscape(name))
+	}
+	return "menu:" + strings.Join(names, "/")
+}
+
+// parseFavourite parse favourite from its key
+func parseFavourite(key string) (Favourite, bool) {
+	var (
+		favourite Favourite
+		names     []string
+		idx       = strings.Index(key, ":")
+	)
+
+	if idx < 0 {
+		return favourite, false
+	}
+	typ, value := key[:idx], key[idx+1:]
+
+	for _, part := range strings.Split(value, "/") {
+		name, err := url.PathUnescape(part)
+		if err != nil || name == "" {
+			return favourite, false
+		}
+		names = append(names, name)
+	}
+
+	switch typ {
+	case "menu":
+		favourite.Menu = names
+		return favourite, true
+	case "saved_filter":
+		if len(names) == 2 {
+			favourite.Resource, favourite.SavedFilter = names[0], names[1]
+			return favourite, true
+		}
+	}
+	return favourite, false
+}
+
+// favouritesContext return a context without resource, so favourites are saved for the user instead of a resource
+func (context *Context) favouritesContext() *Context {
+	ctx := context.clone()
+	ctx.Resource = nil
+	return ctx
+}
+
+// getFavourites load current user's favourites
+func (context *Context) getFavourites() (favourites []Favourite) {
+	if context.Context == nil || context.Admin.SettingsStorage == nil || context.GetDB() == nil {
+		return nil
+	}
+
+	if value, ok := context.Get(FavouritesKey).([]Favourite); ok {
+		return value
+	}
+
+	if err := context.Admin.SettingsStorage.Get(FavouritesKey, &favourites, context.favouritesContext()); err != nil {
+		favourites = nil
+	}
+
+	if context.Settings != nil {
+		context.Set(FavouritesKey, favourites)
+	}
+	return favourites
+}
+
+// saveFavourites save favourites for current user, duplicated favourites are removed
+func (context *Context) saveFavourites(favourites []Favourite) ([]Favourite, error) {
+	var (
+		results = []Favourite{}
+		keys    = map[string]bool{}
+	)
+
+	for _, favourite := range favourites {
+		if key := favourite.Key(); !keys[key] {
+			keys[key] = true
+			results = append(results, favourite)
+		}
+	}
+
+	if err := context.Admin.SettingsStorage.Save(FavouritesKey, results, nil, context.CurrentUser, context.favouritesContext()); err != nil {
+		return results, err
+	}
+
+	if context.Settings != nil {
+		context.Set(FavouritesKey, results)
+	}
+	return results, nil
+}
+
+// updateFavourites change favourites with request form, e.g:
+//
+//	add=menu:Products            pin a menu
+//	remove=saved_filter:orders/Pending   unpin a saved filter
+//	order[]=menu:Orders&order[]=menu:Products   reorder favourites, favourites not in the order are kept at the end
+func updateFavourites(favourites []Favourite, form url.Values) []Favourite {
+	if keys := form["order[]"]; len(keys) > 0 {
+		var ordered, rest []Favourite
+		for _, key := range keys {
+			for _, favourite := range favourites {
+				if favourite.Key() == key {
+					ordered = append(ordered, favourite)
+				}
+			}
+		}
+
+		for _, favourite := range favourites {
+			if !isContainsColumn(keys, favourite.Key()) {
+				rest = append(rest, favourite)
+			}
+		}
+		favourites = append(ordered, rest...)
+	}
+
+	if key := form.Get("remove"); key != "" {
+		var results []Favourite
+		for _, favourite := range favourites {
+			if favourite.Key() != key {
+				results = append(results, favourite)
+			}
+		}
+		favourites = results
+	}
+
+	if favourite, ok := parseFavourite(form.Get("add")); ok {
+		favourites = append(favourites, favourite)
+	}
+
+	return favourites
+}
+
+// isFavourite check if the key is one of current user's favourites
+func (context *Context) isFavourite(key string) bool {
+	for _, favourite := range context.getFavourites() {
+		if favourite.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// savedFilterFavouriteKey return favourite key of a saved filter of current resource
+func (context *Context) savedFilterFavouriteKey(filter SavedFilter) string {
+	return Favourite{Resource: context.resourcePath(), SavedFilter: filter.Name}.Key()
+}
+
+// favouriteMenus convert current user's favourites to menus, menus or saved filters that don't exist anymore or couldn't be read are skipped
+func (context *Context) favouriteMenus() (menus []*menu) {
+	savedFilters := map[string][]SavedFilter{}
+
+	for _, favourite := range context.getFavourites() {
+		key := favourite.Key()
+
+		if favourite.SavedFilter != "" {
+			res := context.Admin.GetResource(favourite.Resource)
+			if res == nil || !res.HasPermission(roles.Read, context.Context) {
+				continue
+			}
+
+			filters, ok := savedFilters[favourite.Resource]
+			if !ok {
+				ctx := context.clone()
+				ctx.Resource = res
+				filters = ctx.savedFilters()
+				savedFilters[favourite.Resource] = filters
+			}
+
+			for _, filter := range filters {
+				if filter.Name == favourite.SavedFilter {
+					menus = append(menus, &menu{Menu: &Menu{Name: filter.Name, IconName: res.Config.IconName, Link: filter.URL}, FavouriteKey: key, Favourited: true})
+					break
+				}
+			}
+			continue
+		}
+
+		if m := context.Admin.GetMenu(favourite.Menu...); m != nil && m.URL() != "" && m.HasPermission(roles.Read, context.Context) {
+			menus = append(menus, &menu{Menu: m, BadgeLabel: context.menuBadge(m), FavouriteKey: key, Favourited: true})
+		}
+	}
+	return menus
+}
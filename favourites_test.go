@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseFavourite(t *testing.T) {
+	for _, favourite := range []Favourite{
+		{Menu: []string{"Products"}},
+		{Menu: []string{"Product Management", "Colors/Sizes"}},
+		{Resource: "orders", SavedFilter: "Paid: last week"},
+	} {
+		parsed, ok := parseFavourite(favourite.Key())
+		if !ok || !reflect.DeepEqual(parsed, favourite) {
+			t.Errorf("favourite %#v should be parsed from key %v, but got %#v", favourite, favourite.Key(), parsed)
+		}
+	}
+
+	for _, key := range []string{"", "Products", "menu:", "saved_filter:orders", "unknown:Products"} {
+		if _, ok := parseFavourite(key); ok {
+			t.Errorf("key %q should be invalid", key)
+		}
+	}
+}
+
+func TestUpdateFavourites(t *testing.T) {
+	var (
+		products = Favourite{Menu: []string{"Products"}}
+		orders   = Favourite{Menu: []string{"Orders"}}
+		paid     = Favourite{Resource: "orders", SavedFilter: "Paid"}
+	)
+
+	favourites := updateFavourites([]Favourite{products}, url.Values{"add": {orders.Key()}})
+	favourites = updateFavourites(favourites, url.Values{"add": {paid.Key()}})
+	if !reflect.DeepEqual(favourites, []Favourite{products, orders, paid}) {
+		t.Errorf("favourites should be added, but got %#v", favourites)
+	}
+
+	favourites = updateFavourites(favourites, url.Values{"order[]": {paid.Key(), products.Key()}})
+	if !reflect.DeepEqual(favourites, []Favourite{paid, products, orders}) {
+		t.Errorf("favourites should be reordered, but got %#v", favourites)
+	}
+
+	favourites = updateFavourites(favourites, url.Values{"remove": {products.Key()}})
+	if !reflect.DeepEqual(favourites, []Favourite{paid, orders}) {
+		t.Errorf("favourite should be removed, but got %#v", favourites)
+	}
+}
@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/simonedbarber/qor/utils"
+)
+
+// fieldErrorKey return key of errors of an input, which is its input name without prefix QorResource., e.g: Name, Addresses[0].City
+func fieldErrorKey(inputName string) string {
+	return strings.TrimPrefix(inputName, "QorResource.")
+}
+
+// validationLabel return label of record's field in validation errors, e.g: User_1_Name
+func (context *Context) validationLabel(record interface{}, fieldName string) string {
+	return fmt.Sprintf("%v_%v_%v", reflect.Indirect(reflect.ValueOf(record)).Type().Name(), context.primaryKeyOf(record), fieldName)
+}
+
+// fieldErrorLabels map labels of validation errors of the record's fields to keys of their inputs, including fields of nested single_edit, collection_edit records
+func (context *Context) fieldErrorLabels(res *Resource, record interface{}, prefix string, labels map[string]string) {
+	// skip values aren't records of the resource, e.g: data of custom pages
+	value := reflect.Indirect(reflect.ValueOf(record))
+	if res == nil || !value.IsValid() || value.Type() != utils.ModelType(res.Value) {
+		return
+	}
+
+	for _, metaor := range res.GetMetas([]string{}) {
+		meta, ok := metaor.(*Meta)
+		if !ok {
+			continue
+		}
+
+		key := prefix + meta.Name
+		if meta.FieldName != "" {
+			addFieldErrorLabel(labels, context.validationLabel(record, meta.FieldName), key)
+			labels[recordErrorLabel(record, meta.FieldName)] = key
+		}
+
+		if meta.Resource == nil || (meta.Type != "single_edit" && meta.Type != "collection_edit") {
+			continue
+		}
+
+		nested := reflect.ValueOf(context.RawValueOf(record, meta))
+		for nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+
+		switch nested.Kind() {
+		case reflect.Slice:
+			for i := 0; i < nested.Len(); i++ {
+				if item := nested.Index(i); item.Kind() == reflect.Ptr {
+					context.fieldErrorLabels(meta.Resource, item.Interface(), fmt.Sprintf("%v[%v].", key, i), labels)
+				} else if item.CanAddr() {
+					context.fieldErrorLabels(meta.Resource, item.Addr().Interface(), fmt.Sprintf("%v[%v].", key, i), labels)
+				}
+			}
+		case reflect.Struct:
+			if nested.CanAddr() {
+				context.fieldErrorLabels(meta.Resource, nested.Addr().Interface(), key+".", labels)
+			}
+		}
+	}
+}
+
+// recordErrorLabel return label of errors of the record's field identified with the record itself, nested records are decoded into their own values by row, so their errors are mapped to rows' inputs even though they have same primary key, e.g: new collection_edit records
+func recordErrorLabel(record interface{}, fieldName string) string {
+	return fmt.Sprintf("%p_%v", record, fieldName)
+}
+
+// errorRecordLabel return record label of validation errors have the record and its field, e.g: validations.Error{Resource: address, Column: "City"}
+func errorRecordLabel(err error) (string, bool) {
+	value := reflect.Indirect(reflect.ValueOf(err))
+	if value.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	record, column := value.FieldByName("Resource"), value.FieldByName("Column")
+	if !record.IsValid() || !record.CanInterface() || column.Kind() != reflect.String {
+		return "", false
+	}
+
+	if r := reflect.ValueOf(record.Interface()); r.Kind() != reflect.Ptr || r.IsNil() {
+		return "", false
+	}
+	return recordErrorLabel(record.Interface(), column.String()), true
+}
+
+// addFieldErrorLabel map the label to key of the input, labels of several inputs are mapped to blank key, e.g: new nested collection_edit records all have primary key 0, so their errors are mapped with recordErrorLabel only
+func addFieldErrorLabel(labels map[string]string, label, key string) {
+	if existing, ok := labels[label]; ok && existing != key {
+		labels[label] = ""
+	} else if !ok {
+		labels[label] = key
+	}
+}
+
+// fieldErrors group errors by keys of inputs, FieldError is keyed by its Field, validation errors are keyed by their fields of the record or nested records, other errors are keyed by blank string, keys are returned in order of errors
+func fieldErrors(errs []error, labels map[string]string) (keys []string, messages map[string][]string) {
+	type labelInterface interface {
+		Label() string
+	}
+
+	messages = map[string][]string{}
+	for _, err := range errs {
+		var key string
+		switch e := err.(type) {
+		case *FieldError:
+			key = e.Field
+		case FieldError:
+			key = e.Field
+		case labelInterface:
+			if label, ok := errorRecordLabel(err); ok && labels[label] != "" {
+				key = labels[label]
+			} else if k, ok := labels[e.Label()]; ok {
+				key = k
+			}
+		}
+
+		if _, ok := messages[key]; !ok {
+			keys = append(keys, key)
+		}
+		messages[key] = append(messages[key], err.Error())
+	}
+	return keys, messages
+}
+
+// FieldErrors return errors of context grouped by keys of inputs, e.g: Name, Addresses[0].City, errors don't belong to any input are keyed by blank string
+func (context *Context) FieldErrors() map[string][]string {
+	labels := map[string]string{}
+	context.fieldErrorLabels(context.Resource, context.Result, "", labels)
+
+	_, messages := fieldErrors(context.GetErrors(), labels)
+	return messages
+}
+
+// errorsOfInput return errors of the input for meta templates
+func (context *Context) errorsOfInput(inputName string) []string {
+	if !context.HasError() {
+		return nil
+	}
+	return context.FieldErrors()[fieldErrorKey(inputName)]
+}
+
+// ProblemDetails RFC 7807 problem details of failed JSON requests, Errors are messages of fields keyed by keys of their inputs, e.g: Name, Addresses[0].City
+type ProblemDetails struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Errors    map[string][]string `json:"errors,omitempty"`
+	Conflicts []VersionDiff       `json:"conflicts,omitempty"`
+}
+
+// newProblemDetails build problem details with status and errors of fields, messages of errors don't belong to fields are joined as detail
+func newProblemDetails(status int, errs []error, labels map[string]string) ProblemDetails {
+	problem := ProblemDetails{Type: "about:blank", Title: http.StatusText(status), Status: status}
+
+	keys, messages := fieldErrors(errs, labels)
+	for _, key := range keys {
+		if key == "" {
+			problem.Detail = strings.Join(messages[key], "; ")
+			continue
+		}
+
+		if problem.Errors == nil {
+			problem.Errors = map[string][]string{}
+		}
+		problem.Errors[key] = messages[key]
+	}
+	return problem
+}
+
+// writeProblemDetails respond errors of context as problem details of the record
+func (context *Context) writeProblemDetails(status int, record interface{}) {
+	labels := map[string]string{}
+	context.fieldErrorLabels(context.Resource, record, "", labels)
+
+	problem := newProblemDetails(status, context.GetErrors(), labels)
+	problem.Instance = context.Request.URL.Path
+	problem.Conflicts = context.versionConflict()
+
+	context.Writer.Header().Set("Content-Type", "application/problem+json")
+	context.Writer.WriteHeader(status)
+	json.NewEncoder(context.Writer).Encode(problem)
+}
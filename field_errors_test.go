@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type labelledError struct {
+	label   string
+	message string
+}
+
+func (err labelledError) Label() string {
+	return err.label
+}
+
+func (err labelledError) Error() string {
+	return err.message
+}
+
+func TestFieldErrorKey(t *testing.T) {
+	for inputName, key := range map[string]string{
+		"QorResource.Name":               "Name",
+		"QorResource.Addresses[0].City":  "Addresses[0].City",
+		"QorResource.Profile.Avatar.URL": "Profile.Avatar.URL",
+	} {
+		if got := fieldErrorKey(inputName); got != key {
+			t.Errorf("key of %v should be %v, but got %v", inputName, key, got)
+		}
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	labels := map[string]string{
+		"User_1_Name":    "Name",
+		"Address_2_City": "Addresses[0].City",
+	}
+
+	keys, messages := fieldErrors([]error{
+		errors.New("failed to save"),
+		labelledError{label: "Address_2_City", message: "city can't be blank"},
+		NewFieldError("Name", "name is taken"),
+		labelledError{label: "User_1_Name", message: "name is too long"},
+		labelledError{label: "Unknown_1_Field", message: "unknown"},
+	}, labels)
+
+	if expected := []string{"", "Addresses[0].City", "Name"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("keys should be %v, but got %v", expected, keys)
+	}
+
+	expected := map[string][]string{
+		"":                  {"failed to save", "unknown"},
+		"Addresses[0].City": {"city can't be blank"},
+		"Name":              {"name is taken", "name is too long"},
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("messages should be %v, but got %v", expected, messages)
+	}
+}
+
+type recordError struct {
+	Resource interface{}
+	Column   string
+	Message  string
+}
+
+func (err recordError) Label() string {
+	return "Address_0_" + err.Column
+}
+
+func (err recordError) Error() string {
+	return err.Message
+}
+
+func TestFieldErrorsOfNewNestedRecords(t *testing.T) {
+	type address struct {
+		City string
+	}
+
+	var (
+		addresses = []address{{}, {}}
+		labels    = map[string]string{}
+	)
+
+	for i, key := range []string{"Addresses[0].City", "Addresses[1].City"} {
+		addFieldErrorLabel(labels, "Address_0_City", key)
+		labels[recordErrorLabel(&addresses[i], "City")] = key
+	}
+
+	_, messages := fieldErrors([]error{
+		recordError{Resource: &addresses[1], Column: "City", Message: "city of second address can't be blank"},
+		recordError{Resource: &address{}, Column: "City", Message: "unknown address"},
+	}, labels)
+
+	expected := map[string][]string{
+		"Addresses[1].City": {"city of second address can't be blank"},
+		"":                  {"unknown address"},
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("messages should be %v, but got %v", expected, messages)
+	}
+}
+
+func TestAddFieldErrorLabel(t *testing.T) {
+	labels := map[string]string{}
+	addFieldErrorLabel(labels, "User_1_Name", "Name")
+	addFieldErrorLabel(labels, "User_1_Name", "Name")
+	for i, key := range []string{"Addresses[0].City", "Addresses[1].City", "Addresses[2].City"} {
+		addFieldErrorLabel(labels, "Address_0_City", key)
+		if i == 0 && labels["Address_0_City"] != key {
+			t.Errorf("label of a new record should be mapped to %v, but got %v", key, labels["Address_0_City"])
+		}
+	}
+
+	expected := map[string]string{"User_1_Name": "Name", "Address_0_City": ""}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("labels should be %v, but got %v", expected, labels)
+	}
+}
+
+func TestNewProblemDetails(t *testing.T) {
+	problem := newProblemDetails(HTTPUnprocessableEntity, []error{
+		NewFieldError("Addresses[1].Zip", "zip is invalid"),
+		errors.New("failed to save"),
+	}, nil)
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"failed to save","errors":{"Addresses[1].Zip":["zip is invalid"]}}`
+	if string(data) != expected {
+		t.Errorf("expected %v, but got %v", expected, string(data))
+	}
+
+	if problem := newProblemDetails(409, nil, nil); problem.Title != "Conflict" || problem.Errors != nil {
+		t.Errorf("unexpected problem details %#v", problem)
+	}
+}
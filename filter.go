@@ -14,17 +14,13 @@ type Filter struct {
 	Name       string
 	Label      string
 	Type       string
-	Operations []string // eq, cont, gt, gteq, lt, lteq
+	Operations []string // eq, ne, cont, gt, gteq, lt, lteq, between
 	Resource   *Resource
 	Visible    func(context *Context) bool
 	Handler    func(*gorm.DB, *FilterArgument) *gorm.DB
 	Config     FilterConfigInterface
-}
-
-// SavedFilter saved filter settings
-type SavedFilter struct {
-	Name string
-	URL  string
+	// ShowCount show count of records next to each option of select one, select many filters, counted with one GROUP BY query
+	ShowCount bool
 }
 
 // FilterConfigInterface filter config interface
@@ -50,6 +46,11 @@ func (res *Resource) Filter(filter *Filter) {
 	if meta := res.GetMeta(filter.Name); meta != nil {
 		if filter.Type == "" {
 			filter.Type = meta.Type
+			// number metas are filtered by range, other types like select_one keep their own filters
+			isNumberMeta := meta.Type == "" || meta.Type == "number" || meta.Type == "float"
+			if isNumberMeta && meta.FieldStruct != nil && numberParser(meta.FieldStruct.StructField.Type) != nil {
+				filter.Type = "number"
+			}
 		}
 
 		if filter.Config == nil {
@@ -66,6 +67,18 @@ func (res *Resource) Filter(filter *Filter) {
 	if filter.Handler == nil {
 		// generate default handler
 		filter.Handler = func(db *gorm.DB, filterArgument *FilterArgument) *gorm.DB {
+			// range of number filter, e.g: filters[Price].Min=10&filters[Price].Max=100
+			var min, max string
+			if metaValue := filterArgument.Value.Get("Min"); metaValue != nil {
+				min = utils.ToString(metaValue.Value)
+			}
+			if metaValue := filterArgument.Value.Get("Max"); metaValue != nil {
+				max = utils.ToString(metaValue.Value)
+			}
+			if min != "" || max != "" {
+				db = filterResourceByFields(res, []filterField{{FieldName: filter.Name, Operation: "between"}}, min+".."+max, db, filterArgument.Context)
+			}
+
 			if metaValue := filterArgument.Value.Get("Value"); metaValue != nil {
 				keyword := utils.ToString(metaValue.Value)
 				if _, ok := filter.Config.(*SelectManyConfig); ok {
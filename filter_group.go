@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/resource"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FilterGroupParam url param used to pass filter group
+const FilterGroupParam = "filter_group"
+
+// MaxFilterGroupDepth max nested levels of a filter group
+var MaxFilterGroupDepth = 8
+
+const (
+	// FilterGroupAnd all conditions of the group should match, it is the default operator
+	FilterGroupAnd = "and"
+	// FilterGroupOr any condition of the group should match
+	FilterGroupOr = "or"
+)
+
+// FilterGroup combine registered filters with AND/OR, groups could be nested and negated, a group with Filter is a condition of the filter, e.g:
+//
+//	// status = failed OR (status = pending AND created before 3 days ago)
+//	&admin.FilterGroup{Operator: admin.FilterGroupOr, Groups: []*admin.FilterGroup{
+//		{Filter: "Status", Values: map[string]interface{}{"Value": "failed"}},
+//		{Groups: []*admin.FilterGroup{
+//			{Filter: "Status", Values: map[string]interface{}{"Value": "pending"}},
+//			{Filter: "CreatedAt", Values: map[string]interface{}{"End": "2024-03-01 00:00"}},
+//		}},
+//	}}
+//
+// it is serialized as JSON in url param `filter_group`, so could be used in saved filters and JSON API:
+//
+//	/admin/orders.json?filter_group={"operator":"or","groups":[{"filter":"Status","values":{"Value":"failed"}},...]}
+type FilterGroup struct {
+	Operator string                 `json:"operator,omitempty"`
+	Not      bool                   `json:"not,omitempty"`
+	Filter   string                 `json:"filter,omitempty"`
+	Values   map[string]interface{} `json:"values,omitempty"`
+	Groups   []*FilterGroup         `json:"groups,omitempty"`
+}
+
+// ParseFilterGroup parse filter group from its JSON format
+func ParseFilterGroup(str string) (*FilterGroup, error) {
+	var group FilterGroup
+	if err := json.Unmarshal([]byte(str), &group); err != nil {
+		return nil, fmt.Errorf("invalid filter group: %v", err)
+	}
+	return &group, nil
+}
+
+// String serialize filter group to JSON
+func (group *FilterGroup) String() string {
+	result, _ := json.Marshal(group)
+	return string(result)
+}
+
+// IsBlank return true if the group has no conditions
+func (group *FilterGroup) IsBlank() bool {
+	if group == nil {
+		return true
+	}
+
+	if group.Filter != "" {
+		return false
+	}
+
+	for _, g := range group.Groups {
+		if !g.IsBlank() {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate check filter group's filters are registered for the resource
+func (group *FilterGroup) Validate(res *Resource) error {
+	return group.validate(res, 1)
+}
+
+func (group *FilterGroup) validate(res *Resource, depth int) error {
+	if depth > MaxFilterGroupDepth {
+		return fmt.Errorf("filter group is nested too deep, max depth is %v", MaxFilterGroupDepth)
+	}
+
+	if group.Operator != "" && group.Operator != FilterGroupAnd && group.Operator != FilterGroupOr {
+		return fmt.Errorf("invalid filter group operator %v", group.Operator)
+	}
+
+	if group.Filter != "" {
+		if len(group.Groups) > 0 {
+			return errors.New("filter condition can't have nested groups")
+		}
+
+		if res.GetFilter(group.Filter) == nil {
+			return fmt.Errorf("unknown filter %v", group.Filter)
+		}
+	}
+
+	for _, g := range group.Groups {
+		if err := g.validate(res, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metaValues convert condition's values to meta values that used by filter handler, same as parsed from `filters[Name].Value=xxx`
+func (group *FilterGroup) metaValues() *resource.MetaValues {
+	metaValues := &resource.MetaValues{}
+	for name, value := range group.Values {
+		var values []string
+		switch v := value.(type) {
+		case []interface{}:
+			for _, s := range v {
+				values = append(values, fmt.Sprint(s))
+			}
+		case []string:
+			values = v
+		case nil:
+		default:
+			values = []string{fmt.Sprint(v)}
+		}
+		metaValues.Values = append(metaValues.Values, &resource.MetaValue{Name: name, Value: values})
+	}
+	return metaValues
+}
+
+// parenthesesExpression wrap an expression with parentheses, so conditions generated by filter handlers won't be mixed with other conditions
+type parenthesesExpression struct {
+	clause.Expression
+}
+
+// Build build expression with parentheses
+func (expr parenthesesExpression) Build(builder clause.Builder) {
+	builder.WriteByte('(')
+	expr.Expression.Build(builder)
+	builder.WriteByte(')')
+}
+
+// applyFilterGroup filter db with filter group
+func (res *Resource) applyFilterGroup(db *gorm.DB, group *FilterGroup, context *qor.Context) *gorm.DB {
+	expr, db := res.filterGroupExpression(db, group, context)
+	if expr == nil {
+		return db
+	}
+	return db.Clauses(clause.Where{Exprs: []clause.Expression{expr}})
+}
+
+// filterGroupExpression build where expression for filter group, joins required by filter handlers will be added to db
+func (res *Resource) filterGroupExpression(db *gorm.DB, group *FilterGroup, context *qor.Context) (clause.Expression, *gorm.DB) {
+	var exprs []clause.Expression
+
+	if group.Filter != "" {
+		filter := res.GetFilter(group.Filter)
+		if filter == nil || filter.Handler == nil {
+			return nil, db
+		}
+
+		// run filter handler with a new db, then move its conditions into the group
+		tx := filter.Handler(db.Session(&gorm.Session{NewDB: true}), &FilterArgument{
+			Value:    group.metaValues(),
+			Resource: res,
+			Context:  context,
+		})
+
+		if where, ok := tx.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+			exprs = append(exprs, where.Exprs...)
+		}
+
+		for _, join := range tx.Statement.Joins {
+			exists := false
+			for _, j := range db.Statement.Joins {
+				if j.Name == join.Name {
+					exists = true
+					break
+				}
+			}
+
+			if !exists {
+				db = db.Joins(join.Name, join.Conds...)
+			}
+		}
+	} else {
+		for _, g := range group.Groups {
+			var expr clause.Expression
+			if expr, db = res.filterGroupExpression(db, g, context); expr != nil {
+				exprs = append(exprs, expr)
+			}
+		}
+	}
+
+	if len(exprs) == 0 {
+		return nil, db
+	}
+
+	var expr clause.Expression
+	if group.Operator == FilterGroupOr {
+		expr = parenthesesExpression{clause.Or(exprs...)}
+	} else {
+		expr = parenthesesExpression{clause.AndConditions{Exprs: exprs}}
+	}
+
+	if group.Not {
+		expr = clause.Not(expr)
+	}
+	return expr, db
+}
+
+// filterGroupValue return current filter group param
+func (context *Context) filterGroupValue() string {
+	if context.Request == nil {
+		return ""
+	}
+	return context.Request.URL.Query().Get(FilterGroupParam)
+}
+
+// filterGroupFilters return filters could be used in filter group builder as JSON
+func (context *Context) filterGroupFilters() string {
+	type filterOption struct {
+		Name       string
+		Label      string
+		Type       string
+		Operations []string
+	}
+
+	var options = []filterOption{}
+	for _, filter := range context.getFilters() {
+		label := string(context.t(fmt.Sprintf("%v.filter.%v", context.Resource.ToParam(), filter.Label), filter.Label))
+		options = append(options, filterOption{Name: filter.Name, Label: label, Type: filter.Type, Operations: filter.Operations})
+	}
+
+	result, _ := json.Marshal(options)
+	return string(result)
+}
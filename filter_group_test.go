@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"testing"
+)
+
+func TestFilterGroup(t *testing.T) {
+	res := &Resource{filters: []*Filter{{Name: "Status"}, {Name: "CreatedAt"}}}
+
+	group, err := ParseFilterGroup(`{"operator":"or","groups":[{"filter":"Status","values":{"Value":"failed"}},{"groups":[{"filter":"Status","values":{"Value":"pending"}},{"filter":"CreatedAt","not":true,"values":{"End":"2024-03-01"}}]}]}`)
+	if err != nil {
+		t.Fatalf("failed to parse filter group, got %v", err)
+	}
+
+	if err := group.Validate(res); err != nil {
+		t.Errorf("filter group should be valid, but got %v", err)
+	}
+
+	if group.IsBlank() {
+		t.Errorf("filter group shouldn't be blank")
+	}
+
+	if parsed, _ := ParseFilterGroup(group.String()); parsed.String() != group.String() {
+		t.Errorf("filter group should be serialized as %v, but got %v", group.String(), parsed.String())
+	}
+
+	if value := group.Groups[0].metaValues().Get("Value"); value == nil || value.Value.([]string)[0] != "failed" {
+		t.Errorf("filter condition's values should be converted to meta values")
+	}
+
+	for _, str := range []string{
+		`{"operator":"xor"}`,
+		`{"filter":"Unknown"}`,
+		`{"filter":"Status","groups":[{"filter":"Status"}]}`,
+	} {
+		if group, err := ParseFilterGroup(str); err != nil || group.Validate(res) == nil {
+			t.Errorf("filter group %v should be invalid", str)
+		}
+	}
+
+	if !(&FilterGroup{Groups: []*FilterGroup{{}}}).IsBlank() {
+		t.Errorf("filter group without conditions should be blank")
+	}
+}
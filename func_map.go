@@ -106,8 +106,23 @@ func (context *Context) FuncMap() template.FuncMap {
 			context.renderMeta(meta, value, []string{}, typ, result)
 			return template.HTML(result.String())
 		},
-		"render_filter": context.renderFilter,
-		"saved_filters": context.savedFilters,
+		"render_filter":              context.renderFilter,
+		"saved_filters":              context.savedFilters,
+		"saved_filter_favourite_key": context.savedFilterFavouriteKey,
+		"is_favourite":               context.isFavourite,
+		"record_version": func(record interface{}) string {
+			if context.Resource == nil {
+				return ""
+			}
+			return context.Resource.RecordVersion(record)
+		},
+		"version_conflict":        context.versionConflict,
+		"edit_lock_holder":        context.editLockHolder,
+		"edit_lock_url":           context.editLockURL,
+		"edit_lock_interval":      context.editLockInterval,
+		"edit_lock_message":       context.editLockMessage,
+		"can_take_over_edit_lock": context.canTakeOverEditLock,
+		"live_events_url":         context.liveEventsURL,
 		"has_filter": func() bool {
 			query := context.Request.URL.Query()
 			for key := range query {
@@ -115,9 +130,15 @@ func (context *Context) FuncMap() template.FuncMap {
 					return true
 				}
 			}
-			return false
+			return query.Get(FilterGroupParam) != ""
 		},
-		"page_title": context.pageTitle,
+		"search_query_text":          context.searchQueryText,
+		"search_query_errors":        context.searchQueryErrors,
+		"search_query_hidden_params": context.searchQueryHiddenParams,
+		"datetime_presets":           func() []*DatetimePreset { return DatetimePresets },
+		"filter_group":               context.filterGroupValue,
+		"filter_group_filters":       context.filterGroupFilters,
+		"page_title":                 context.pageTitle,
 		"meta_label": func(meta *Meta) template.HTML {
 			key := fmt.Sprintf("%v.attributes.%v", meta.baseResource.ToParam(), meta.Label)
 			return context.Admin.T(context.Context, key, meta.Label)
@@ -135,15 +156,18 @@ func (context *Context) FuncMap() template.FuncMap {
 			return context.Admin.T(context.Context, key, placeholder)
 		},
 
-		"url_for":            context.URLFor,
-		"link_to":            context.linkTo,
-		"patch_current_url":  context.patchCurrentURL,
-		"patch_url":          context.patchURL,
-		"join_current_url":   context.joinCurrentURL,
-		"join_url":           context.joinURL,
-		"logout_url":         context.logoutURL,
-		"search_center_path": func() string { return path.Join(context.Admin.router.Prefix, "!search") },
-		"new_resource_path":  context.newResourcePath,
+		"url_for":              context.URLFor,
+		"link_to":              context.linkTo,
+		"patch_current_url":    context.patchCurrentURL,
+		"patch_url":            context.patchURL,
+		"join_current_url":     context.joinCurrentURL,
+		"join_url":             context.joinURL,
+		"logout_url":           context.logoutURL,
+		"search_center_path":   func() string { return path.Join(context.Admin.router.Prefix, "!search") },
+		"command_palette_path": func() string { return path.Join(context.Admin.router.Prefix, "!command_palette") },
+		"delete_preview_url":   context.deletePreviewURL,
+		"favourites_path":      func() string { return path.Join(context.Admin.router.Prefix, "!favourites") },
+		"new_resource_path":    context.newResourcePath,
 		"defined_resource_show_page": func(res *Resource) bool {
 			if res != nil {
 				if r := context.Admin.GetResource(utils.ModelType(res.Value).String()); r != nil {
@@ -156,12 +180,16 @@ func (context *Context) FuncMap() template.FuncMap {
 
 		"get_menus":            context.getMenus,
 		"get_scopes":           context.getScopes,
+		"filter_option_count":  context.filterOptionCount,
 		"get_filters":          context.getFilters,
 		"get_formatted_errors": context.getFormattedErrors,
 		"load_actions":         context.loadActions,
 		"allowed_actions":      context.AllowedActions,
 		"is_sortable_meta":     context.isSortableMeta,
+		"meta_sort_order":      context.metaSortOrder,
 		"index_sections":       context.indexSections,
+		"index_settings":       context.getIndexSettings,
+		"index_columns":        context.indexColumns,
 
 		"show_sections": context.showSections,
 		"show_layout":   context.showLayout,
@@ -346,6 +374,8 @@ type Page struct {
 type PaginationResult struct {
 	Pagination Pagination
 	Pages      []Page
+	// Cursor is true if paginate with cursors, there are only previous, next pages then, use Pagination.PrevCursor, Pagination.NextCursor to link them
+	Cursor bool
 }
 
 // Pagination return pagination information
@@ -373,7 +403,15 @@ func (context *Context) Pagination() *PaginationResult {
 		}
 	}
 
-	if pagination.Total <= pageCount && pagination.CurrentPage <= 1 {
+	if pagination.UseCursor {
+		return &PaginationResult{Pagination: pagination, Cursor: true}
+	}
+
+	if pagination.TotalSkipped {
+		if pagination.Pages <= 1 && pagination.CurrentPage <= 1 {
+			return &PaginationResult{Pagination: pagination, Pages: pages}
+		}
+	} else if pagination.Total <= pageCount && pagination.CurrentPage <= 1 {
 		return &PaginationResult{Pagination: pagination, Pages: pages}
 	}
 
@@ -407,7 +445,9 @@ func (context *Context) Pagination() *PaginationResult {
 	// Append next link
 	if end < pagination.Pages {
 		pages = append(pages, Page{Page: pagination.CurrentPage + 1, IsNext: true})
-		pages = append(pages, Page{Page: pagination.Pages, IsLast: true})
+		if !pagination.TotalSkipped {
+			pages = append(pages, Page{Page: pagination.Pages, IsLast: true})
+		}
 	}
 
 	return &PaginationResult{Pagination: pagination, Pages: pages}
@@ -675,11 +715,6 @@ func (context *Context) renderFilter(filter *Filter) template.HTML {
 	return template.HTML(result.String())
 }
 
-func (context *Context) savedFilters() (filters []SavedFilter) {
-	context.Admin.SettingsStorage.Get("saved_filters", &filters, context)
-	return
-}
-
 func (context *Context) renderMeta(meta *Meta, value interface{}, prefix []string, metaType string, writer *bytes.Buffer) {
 	var (
 		err      error
@@ -763,6 +798,7 @@ func (context *Context) renderMeta(meta *Meta, value interface{}, prefix []strin
 			"Value":         context.FormattedValueOf(value, meta),
 			"Label":         meta.Label,
 			"InputName":     strings.Join(prefix, "."),
+			"Errors":        context.errorsOfInput(strings.Join(prefix, ".")),
 		}
 
 		if !utils.PrimaryKeyZero(value) {
@@ -869,7 +905,43 @@ func (context *Context) getResource(resources ...*Resource) *Resource {
 
 func (context *Context) indexSections(resources ...*Resource) []*Section {
 	res := context.getResource(resources...)
-	return res.allowedSections(res.IndexAttrs(), context, roles.Read)
+	sections := res.allowedSections(res.IndexAttrs(), context, roles.Read)
+	if res == context.Resource {
+		sections = res.userIndexSections(sections, context)
+	}
+	return sections
+}
+
+type indexColumn struct {
+	*Meta
+	Visible bool
+}
+
+// indexColumns return columns could be chosen in index settings, visible columns come first with user's order
+func (context *Context) indexColumns() (columns []*indexColumn) {
+	if context.Resource == nil {
+		return
+	}
+
+	var (
+		settings  = context.getIndexSettings()
+		available = context.Resource.availableIndexMetas(context)
+	)
+
+	for _, name := range settings.Columns {
+		for _, meta := range available {
+			if meta.Name == name {
+				columns = append(columns, &indexColumn{Meta: meta, Visible: true})
+			}
+		}
+	}
+
+	for _, meta := range available {
+		if len(settings.Columns) == 0 || !isContainsColumn(settings.Columns, meta.Name) {
+			columns = append(columns, &indexColumn{Meta: meta, Visible: len(settings.Columns) == 0})
+		}
+	}
+	return
 }
 
 func (context *Context) editSections(resources ...*Resource) []*Section {
@@ -911,8 +983,15 @@ func (context *Context) showLayout(resources ...*Resource) (layout *Layout) {
 
 type menu struct {
 	*Menu
-	Active   bool
-	SubMenus []*menu
+	Active bool
+	// BadgeLabel evaluated badge of the menu
+	BadgeLabel string
+	// FavouriteKey key to pin the menu to favourites, Favourited the menu has been pinned
+	FavouriteKey string
+	Favourited   bool
+	// IsFavourites the menu is the group of user's favourites
+	IsFavourites bool
+	SubMenus     []*menu
 }
 
 func (context *Context) getMenus() (menus []*menu) {
@@ -920,20 +999,29 @@ func (context *Context) getMenus() (menus []*menu) {
 		globalMenu        = &menu{}
 		mostMatchedMenu   *menu
 		mostMatchedLength int
-		addMenu           func(*menu, []*Menu)
+		addMenu           func(*menu, []*Menu, []string)
 	)
 
-	addMenu = func(parent *menu, menus []*Menu) {
+	addMenu = func(parent *menu, menus []*Menu, ancestors []string) {
 		for _, m := range menus {
 			url := m.URL()
 			if m.HasPermission(roles.Read, context.Context) {
-				var menu = &menu{Menu: m}
+				var (
+					names = append(append([]string{}, ancestors...), m.Name)
+					menu  = &menu{Menu: m, BadgeLabel: context.menuBadge(m)}
+				)
+
 				if strings.HasPrefix(context.Request.URL.Path, url) && len(url) > mostMatchedLength {
 					mostMatchedMenu = menu
 					mostMatchedLength = len(url)
 				}
 
-				addMenu(menu, menu.GetSubMenus())
+				if url != "" {
+					menu.FavouriteKey = Favourite{Menu: names}.Key()
+					menu.Favourited = context.isFavourite(menu.FavouriteKey)
+				}
+
+				addMenu(menu, menu.GetSubMenus(), names)
 				if len(menu.SubMenus) > 0 || menu.URL() != "" {
 					parent.SubMenus = append(parent.SubMenus, menu)
 				}
@@ -941,18 +1029,25 @@ func (context *Context) getMenus() (menus []*menu) {
 		}
 	}
 
-	addMenu(globalMenu, context.Admin.GetMenus())
+	addMenu(globalMenu, context.Admin.GetMenus(), nil)
 
 	if context.Action != "search_center" && mostMatchedMenu != nil {
 		mostMatchedMenu.Active = true
 	}
 
+	if favourites := context.favouriteMenus(); len(favourites) > 0 {
+		favouritesMenu := &menu{Menu: &Menu{Name: FavouritesMenuName, IconName: "Favourites"}, IsFavourites: true, SubMenus: favourites}
+		globalMenu.SubMenus = append([]*menu{favouritesMenu}, globalMenu.SubMenus...)
+	}
+
 	return globalMenu.SubMenus
 }
 
 type scope struct {
 	*Scope
 	Active bool
+	// Count formatted count of records, blank if the scope doesn't show count
+	Count string
 }
 
 type scopeMenu struct {
@@ -1012,10 +1107,37 @@ OUT:
 				}
 			}
 		}
+
+		context.setScopeCounts(menu)
 	}
 	return menus
 }
 
+// setScopeCounts count scopes of a scope menu that show counts
+func (context *Context) setScopeCounts(menu *scopeMenu) {
+	if context.Searcher == nil {
+		return
+	}
+
+	var scopes []*Scope
+	for _, scope := range menu.Scopes {
+		if scope.ShowCount {
+			scopes = append(scopes, scope.Scope)
+		}
+	}
+
+	if len(scopes) > 0 {
+		// counts are left blank if failed to count, the scopes still work
+		if counts, err := context.Searcher.scopeCounts(scopes); err == nil {
+			for _, scope := range menu.Scopes {
+				if count, ok := counts[scope.Name]; ok {
+					scope.Count = formatCount(count)
+				}
+			}
+		}
+	}
+}
+
 // getFilters get filters from current context
 func (context *Context) getFilters() (filters []*Filter) {
 	if context.Resource == nil {
@@ -1289,12 +1411,7 @@ func (context *Context) t(values ...interface{}) template.HTML {
 }
 
 func (context *Context) isSortableMeta(meta *Meta) bool {
-	for _, attr := range context.Resource.SortableAttrs() {
-		if attr == meta.Name && meta.FieldStruct != nil && meta.FieldStruct.DBName != "" {
-			return true
-		}
-	}
-	return false
+	return context.Resource.sortKeyOfMeta(meta) != ""
 }
 
 func (context *Context) convertSectionToMetas(res *Resource, sections []*Section) []*Meta {
@@ -1306,26 +1423,14 @@ type formatedError struct {
 	Errors []string
 }
 
+// getFormattedErrors group errors by keys of inputs, e.g: Name, Addresses[0].City, errors don't belong to any input have blank label
 func (context *Context) getFormattedErrors() (formatedErrors []formatedError) {
-	type labelInterface interface {
-		Label() string
-	}
+	labels := map[string]string{}
+	context.fieldErrorLabels(context.Resource, context.Result, "", labels)
 
-	for _, err := range context.GetErrors() {
-		if labelErr, ok := err.(labelInterface); ok {
-			var found bool
-			label := labelErr.Label()
-			for _, formatedError := range formatedErrors {
-				if formatedError.Label == label {
-					formatedError.Errors = append(formatedError.Errors, err.Error())
-				}
-			}
-			if !found {
-				formatedErrors = append(formatedErrors, formatedError{Label: label, Errors: []string{err.Error()}})
-			}
-		} else {
-			formatedErrors = append(formatedErrors, formatedError{Errors: []string{err.Error()}})
-		}
+	keys, messages := fieldErrors(context.GetErrors(), labels)
+	for _, key := range keys {
+		formatedErrors = append(formatedErrors, formatedError{Label: key, Errors: messages[key]})
 	}
 	return
 }
@@ -1532,9 +1637,9 @@ func (context *Context) getColumns(data interface{}, fieldName string) (any, err
 func (context *Context) getNewResources() ([]*Resource, error) {
 	var resources []*Resource
 	for _, res := range context.Admin.GetResources() {
-		//		if res.HasPermission(roles.Create, &qor.Context{CurrentUser: context.Context.CurrentUser}) {
-		resources = append(resources, res)
-		//		}
+		if res.HasPermission(roles.Create, context.Context) {
+			resources = append(resources, res)
+		}
 	}
 	return resources, nil
 }
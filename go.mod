@@ -7,6 +7,7 @@ require (
 	github.com/fatih/color v1.15.0
 	github.com/jinzhu/inflection v1.0.0
 	github.com/jinzhu/now v1.1.5
+	github.com/shopspring/decimal v1.2.0
 	github.com/simonedbarber/assetfs v0.0.0-20230903054518-6ea1dea7b87f
 	github.com/simonedbarber/go-template/html/template v0.0.0-20221126165642-dd00c6627d57
 	github.com/simonedbarber/media v0.0.0-20230903023302-e334c4bf929b
@@ -17,6 +18,7 @@ require (
 	github.com/theplant/cldr v0.0.0-20190423050709-9f76f7ce4ee8
 	github.com/theplant/htmltestingutils v0.0.0-20190423050759-0e06de7b6967
 	github.com/theplant/testingutils v0.0.0-20220314083015-b74d1aa8ac8a
+	gorm.io/driver/sqlite v1.5.3
 	gorm.io/gorm v1.25.4
 )
 
@@ -56,7 +58,6 @@ require (
 	github.com/qor/roles v0.0.0-20171127035124-d6375609fe3e // indirect
 	github.com/qor/session v0.0.0-20170907035918-8206b0adab70 // indirect
 	github.com/qor/validations v0.0.0-20171228122639-f364bca61b46 // indirect
-	github.com/shopspring/decimal v1.2.0 // indirect
 	github.com/simonedbarber/audited v0.0.0-20171228121055-b52c9c2f0571 // indirect
 	github.com/simonedbarber/go-template v0.0.0-20221126165201-1b26d8bcac09 // indirect
 	github.com/simonedbarber/go-template/text/template v0.0.0-20221126163055-d93db0c78416 // indirect
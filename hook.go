@@ -0,0 +1,237 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/simonedbarber/qor"
+	"gorm.io/gorm"
+)
+
+// kinds of lifecycle hooks
+const (
+	HookBeforeCreate = "before_create"
+	HookAfterCreate  = "after_create"
+	HookBeforeUpdate = "before_update"
+	HookAfterUpdate  = "after_update"
+	HookBeforeDelete = "before_delete"
+	HookAfterDelete  = "after_delete"
+	HookBeforeAction = "before_action"
+	HookAfterAction  = "after_action"
+)
+
+// HookArgument argument of lifecycle hooks
+type HookArgument struct {
+	Context *Context
+	// Old record before the change, nil when creating
+	Old interface{}
+	// New record to save or saved, nil when deleting, for actions it is the record after executing the action, nil if the action deleted it
+	New interface{}
+	// Tx transaction of the change, queries of hooks should use it, it is rolled back if any hook returns error
+	Tx *gorm.DB
+	// Action executing action, only for action hooks
+	Action *Action
+}
+
+// HookHandler lifecycle hook, returning error aborts the change, return FieldError to show the error on a field, or qor.Errors for multiple errors
+type HookHandler func(argument *HookArgument) error
+
+// FieldError error of a field, e.g: returned by lifecycle hooks
+type FieldError struct {
+	// Field meta name of the field, fields of nested records are like Addresses[0].City
+	Field   string
+	Message string
+}
+
+// NewFieldError new error of the field
+func NewFieldError(field, message string) *FieldError {
+	return &FieldError{Field: field, Message: message}
+}
+
+// Error return message of the error
+func (err FieldError) Error() string {
+	return err.Message
+}
+
+// addHook add lifecycle hook of the kind
+func (res *Resource) addHook(kind string, handler HookHandler) {
+	if res.hooks == nil {
+		res.hooks = map[string][]HookHandler{}
+	}
+	res.hooks[kind] = append(res.hooks[kind], handler)
+}
+
+// BeforeCreate register hook called before saving a new record in admin or the JSON API, e.g:
+//
+//	order.BeforeCreate(func(argument *admin.HookArgument) error {
+//		if argument.New.(*Order).Total < 0 {
+//			return admin.NewFieldError("Total", "total couldn't be negative")
+//		}
+//		return nil
+//	})
+func (res *Resource) BeforeCreate(handler HookHandler) {
+	res.addHook(HookBeforeCreate, handler)
+}
+
+// AfterCreate register hook called after a new record is saved, in the same transaction
+func (res *Resource) AfterCreate(handler HookHandler) {
+	res.addHook(HookAfterCreate, handler)
+}
+
+// BeforeUpdate register hook called before saving changes of a record, Old is the record before the change
+func (res *Resource) BeforeUpdate(handler HookHandler) {
+	res.addHook(HookBeforeUpdate, handler)
+}
+
+// AfterUpdate register hook called after changes of a record are saved, in the same transaction
+func (res *Resource) AfterUpdate(handler HookHandler) {
+	res.addHook(HookAfterUpdate, handler)
+}
+
+// BeforeDelete register hook called before deleting a record
+func (res *Resource) BeforeDelete(handler HookHandler) {
+	res.addHook(HookBeforeDelete, handler)
+}
+
+// AfterDelete register hook called after a record is deleted, in the same transaction
+func (res *Resource) AfterDelete(handler HookHandler) {
+	res.addHook(HookAfterDelete, handler)
+}
+
+// BeforeAction register hook called before executing an action for each selected record, actions of resources with action hooks are executed in a transaction
+func (res *Resource) BeforeAction(handler HookHandler) {
+	res.addHook(HookBeforeAction, handler)
+}
+
+// AfterAction register hook called after executing an action for each selected record, in the same transaction
+func (res *Resource) AfterAction(handler HookHandler) {
+	res.addHook(HookAfterAction, handler)
+}
+
+// hasHooks check if the resource has hooks of any kinds
+func (res *Resource) hasHooks(kinds ...string) bool {
+	for _, kind := range kinds {
+		if len(res.hooks[kind]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// callHooks call hooks of the kind in order, following hooks are skipped once a hook returns error
+func (res *Resource) callHooks(kind string, argument *HookArgument) error {
+	for _, handler := range res.hooks[kind] {
+		if err := handler(argument); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findOldRecord find record of context before changing it, it is only loaded when the resource has hooks of the kinds
+func (res *Resource) findOldRecord(context *qor.Context, kinds ...string) (interface{}, error) {
+	if !res.hasHooks(kinds...) {
+		return nil, nil
+	}
+
+	record := res.NewStruct()
+	if res.Config.Singleton {
+		return record, res.CallFindMany(record, context)
+	}
+	return record, res.CallFindOne(record, nil, context)
+}
+
+// saveWithHooks save New of the argument with hooks before and after saving
+func (res *Resource) saveWithHooks(before, after string, argument *HookArgument, qorCtx *qor.Context) error {
+	if err := res.callHooks(before, argument); err != nil {
+		return err
+	}
+
+	if err := res.CallSave(argument.New, qorCtx); err != nil {
+		return err
+	}
+	return res.callHooks(after, argument)
+}
+
+// createRecord save the new record with create hooks in a transaction
+func (res *Resource) createRecord(record interface{}, context *Context) error {
+	return context.GetDB().Transaction(func(tx *gorm.DB) error {
+		qorCtx := context.Context.Clone()
+		qorCtx.SetDB(tx)
+		return res.saveWithHooks(HookBeforeCreate, HookAfterCreate, &HookArgument{Context: context, New: record, Tx: tx}, qorCtx)
+	})
+}
+
+// updateRecord save changes of the record with update hooks, hooks are called with qorCtx's DB as transaction
+func (res *Resource) updateRecord(old, record interface{}, context *Context, qorCtx *qor.Context) error {
+	return res.saveWithHooks(HookBeforeUpdate, HookAfterUpdate, &HookArgument{Context: context, Old: old, New: record, Tx: qorCtx.GetDB()}, qorCtx)
+}
+
+// executeAction execute the action, if the resource has action hooks, the action is executed in a transaction with hooks called for each selected record
+func (res *Resource) executeAction(action *Action, argument *ActionArgument) error {
+	if !res.hasHooks(HookBeforeAction, HookAfterAction) {
+		return action.Handler(argument)
+	}
+
+	var (
+		context    = argument.Context
+		originalDB = context.GetDB()
+	)
+	defer context.SetDB(originalDB)
+
+	return originalDB.Transaction(func(tx *gorm.DB) error {
+		context.SetDB(tx)
+
+		var (
+			arguments     = []*HookArgument{}
+			primaryValues []string
+			findCtx       = context.Context
+		)
+
+		// actions of trash, e.g: Restore, Purge, are executed on soft deleted records
+		if isTrashRequest(context) || res.isTrashAction(action) {
+			findCtx = context.Context.Clone()
+			findCtx.SetDB(tx.Unscoped())
+		}
+
+		for _, primaryValue := range argument.PrimaryValues {
+			// selected records might have been deleted, they are skipped like FindSelectedRecords
+			olds, err := res.findDeletingRecords([]string{primaryValue}, findCtx)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			arguments = append(arguments, &HookArgument{Context: context, Old: olds[0], Tx: tx, Action: action})
+			primaryValues = append(primaryValues, primaryValue)
+		}
+
+		if len(arguments) == 0 {
+			arguments = append(arguments, &HookArgument{Context: context, Tx: tx, Action: action})
+		}
+
+		for _, hookArgument := range arguments {
+			if err := res.callHooks(HookBeforeAction, hookArgument); err != nil {
+				return err
+			}
+		}
+
+		if err := action.Handler(argument); err != nil {
+			return err
+		}
+
+		for idx, hookArgument := range arguments {
+			if idx < len(primaryValues) {
+				// records deleted by the action are passed with New nil
+				if news, err := res.findDeletingRecords([]string{primaryValues[idx]}, context.Context); err == nil {
+					hookArgument.New = news[0]
+				}
+			}
+
+			if err := res.callHooks(HookAfterAction, hookArgument); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCallHooks(t *testing.T) {
+	var (
+		res    = &Resource{}
+		called []string
+	)
+
+	if res.hasHooks(HookBeforeUpdate) {
+		t.Errorf("resource shouldn't have hooks")
+	}
+
+	res.BeforeUpdate(func(argument *HookArgument) error {
+		called = append(called, "first")
+		return nil
+	})
+	res.BeforeUpdate(func(argument *HookArgument) error {
+		called = append(called, "second")
+		if argument.New == nil {
+			return NewFieldError("Name", "name can't be blank")
+		}
+		return nil
+	})
+	res.BeforeUpdate(func(argument *HookArgument) error {
+		called = append(called, "third")
+		return nil
+	})
+
+	if !res.hasHooks(HookBeforeCreate, HookBeforeUpdate) || res.hasHooks(HookAfterUpdate) {
+		t.Errorf("hooks should be registered by kinds")
+	}
+
+	if err := res.callHooks(HookBeforeUpdate, &HookArgument{New: "record"}); err != nil || len(called) != 3 {
+		t.Errorf("all hooks should be called, but got %v, %v", called, err)
+	}
+
+	called = nil
+	err := res.callHooks(HookBeforeUpdate, &HookArgument{})
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Name" || err.Error() != "name can't be blank" {
+		t.Errorf("field error should be returned, but got %#v", err)
+	}
+
+	if len(called) != 2 {
+		t.Errorf("hooks after the failed hook should be skipped, but got %v", called)
+	}
+
+	if err := res.callHooks(HookAfterDelete, &HookArgument{}); err != nil {
+		t.Errorf("calling kinds without hooks should succeed, but got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/simonedbarber/roles"
+)
+
+// IndexSettingsKey settings key used to save user's index page preferences
+const IndexSettingsKey = "index_settings"
+
+// IndexDensities available densities of index tables
+var IndexDensities = []string{"comfortable", "compact"}
+
+// IndexSettings user's preferences for a resource's index page, saved with SettingsStorage per user and resource
+type IndexSettings struct {
+	Columns []string
+	PerPage int
+	OrderBy string
+	Density string
+}
+
+// IsDefault return true if nothing has been customized
+func (settings IndexSettings) IsDefault() bool {
+	return len(settings.Columns) == 0 && settings.PerPage == 0 && settings.OrderBy == "" && settings.Density == ""
+}
+
+// indexSettingsCacheKey return context key of cached index settings of current resource, contexts might be used for several resources, e.g: menu badges
+func (context *Context) indexSettingsCacheKey() string {
+	return IndexSettingsKey + ":" + context.Resource.ToParam()
+}
+
+// getIndexSettings load current user's index settings for current resource
+func (context *Context) getIndexSettings() IndexSettings {
+	var settings IndexSettings
+	if context.Resource == nil || context.Context == nil || context.Admin.SettingsStorage == nil || context.GetDB() == nil {
+		return settings
+	}
+
+	if value, ok := context.Get(context.indexSettingsCacheKey()).(IndexSettings); ok {
+		return value
+	}
+
+	if err := context.Admin.SettingsStorage.Get(IndexSettingsKey, &settings, context); err != nil {
+		settings = IndexSettings{}
+	}
+
+	// column preset of saved filters
+	if context.Request != nil && context.Request.Method == "GET" {
+		if columns := context.Request.URL.Query().Get("columns"); columns != "" {
+			settings.Columns = strings.Split(columns, ",")
+		}
+	}
+	settings = context.Resource.sanitizeIndexSettings(settings, context)
+
+	if context.Settings != nil {
+		context.Set(context.indexSettingsCacheKey(), settings)
+	}
+	return settings
+}
+
+// saveIndexSettings save index settings for current user and resource, pass blank settings to reset to default
+func (context *Context) saveIndexSettings(settings IndexSettings) (IndexSettings, error) {
+	settings = context.Resource.sanitizeIndexSettings(settings, context)
+	if err := context.Admin.SettingsStorage.Save(IndexSettingsKey, settings, context.Resource, context.CurrentUser, context); err != nil {
+		return settings, err
+	}
+
+	if context.Settings != nil {
+		context.Set(context.indexSettingsCacheKey(), settings)
+	}
+	return settings, nil
+}
+
+// indexSettingsFromRequest parse index settings from request form, e.g:
+//
+//	columns[]=Name&columns[]=State&per_page=50&order_by=created_at_desc&density=compact
+func indexSettingsFromRequest(context *Context) IndexSettings {
+	var (
+		settings IndexSettings
+		form     = context.Request.Form
+	)
+
+	for _, key := range []string{"columns[]", "columns"} {
+		for _, value := range form[key] {
+			for _, column := range strings.Split(value, ",") {
+				if column = strings.TrimSpace(column); column != "" {
+					settings.Columns = append(settings.Columns, column)
+				}
+			}
+		}
+	}
+
+	if perPage, err := strconv.Atoi(form.Get("per_page")); err == nil {
+		settings.PerPage = perPage
+	}
+	settings.OrderBy = form.Get("order_by")
+	settings.Density = form.Get("density")
+	return settings
+}
+
+// availableIndexMetas return metas configured with IndexAttrs that current user could read
+func (res *Resource) availableIndexMetas(context *Context) []*Meta {
+	return res.ConvertSectionToMetas(res.allowedSections(res.IndexAttrs(), context, roles.Read))
+}
+
+// sanitizeIndexSettings drop columns, sorting, page size that are not allowed for current user
+func (res *Resource) sanitizeIndexSettings(settings IndexSettings, context *Context) IndexSettings {
+	var (
+		columns   []string
+		available = res.availableIndexMetas(context)
+	)
+
+	for _, column := range settings.Columns {
+		for _, meta := range available {
+			if meta.Name == column && !isContainsColumn(columns, column) {
+				columns = append(columns, column)
+				break
+			}
+		}
+	}
+	settings.Columns = columns
+
+	if settings.PerPage < 0 || settings.PerPage > MaxPerPageCount {
+		settings.PerPage = 0
+	}
+
+	if settings.OrderBy != "" && !res.isSortableOrder(settings.OrderBy) {
+		settings.OrderBy = ""
+	}
+
+	validDensity := false
+	for _, density := range IndexDensities {
+		if settings.Density == density {
+			validDensity = true
+		}
+	}
+	if !validDensity {
+		settings.Density = ""
+	}
+
+	return settings
+}
+
+// userIndexSections apply user's column preferences to index sections
+func (res *Resource) userIndexSections(sections []*Section, context *Context) []*Section {
+	settings := context.getIndexSettings()
+	if len(settings.Columns) == 0 {
+		return sections
+	}
+	return []*Section{{Resource: res, Rows: [][]string{settings.Columns}}}
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/simonedbarber/qor"
+)
+
+func TestIndexSettingsFromRequest(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/admin/users/!index_settings?columns[]=Name&columns[]=Role,Age&per_page=50&order_by=name_desc&density=compact", nil)
+	req.ParseForm()
+
+	settings := indexSettingsFromRequest(&Context{Context: &qor.Context{Request: req}})
+
+	if !reflect.DeepEqual(settings.Columns, []string{"Name", "Role", "Age"}) {
+		t.Errorf("columns should be parsed in order, but got %v", settings.Columns)
+	}
+
+	if settings.PerPage != 50 {
+		t.Errorf("per page should be 50, but got %v", settings.PerPage)
+	}
+
+	if settings.OrderBy != "name_desc" {
+		t.Errorf("order by should be name_desc, but got %v", settings.OrderBy)
+	}
+
+	if settings.Density != "compact" {
+		t.Errorf("density should be compact, but got %v", settings.Density)
+	}
+
+	if settings.IsDefault() {
+		t.Errorf("customized settings should not be default")
+	}
+
+	if !(IndexSettings{}).IsDefault() {
+		t.Errorf("blank settings should be default")
+	}
+}
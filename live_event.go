@@ -0,0 +1,303 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+// types of live events
+const (
+	LiveEventCreated = "created"
+	LiveEventUpdated = "updated"
+	LiveEventDeleted = "deleted"
+)
+
+// LiveEventsKeepAlive interval of comments sent to keep event streams alive
+var LiveEventsKeepAlive = 30 * time.Second
+
+// LiveEvent a change of resource's records, sent to index pages with Server-Sent Events
+type LiveEvent struct {
+	Type      string
+	Resource  string
+	RecordIDs []string
+	UserID    string `json:",omitempty"`
+	UserName  string `json:",omitempty"`
+}
+
+// Broadcaster deliver live events to subscribers of resources, refer MemoryBroadcaster, PostgresBroadcaster
+type Broadcaster interface {
+	// Publish send the event to subscribers of its resource
+	Publish(event LiveEvent) error
+	// Subscribe subscribe events of the resource until cancel is called
+	Subscribe(resource string) (events <-chan LiveEvent, cancel func())
+}
+
+// MemoryBroadcaster deliver live events in current process, it is the default broadcaster
+type MemoryBroadcaster struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[chan LiveEvent]bool
+}
+
+// NewMemoryBroadcaster new memory broadcaster
+func NewMemoryBroadcaster() *MemoryBroadcaster {
+	return &MemoryBroadcaster{subscribers: map[string]map[chan LiveEvent]bool{}}
+}
+
+// Publish send the event to subscribers of its resource, events are dropped for slow subscribers
+func (broadcaster *MemoryBroadcaster) Publish(event LiveEvent) error {
+	broadcaster.mutex.RLock()
+	defer broadcaster.mutex.RUnlock()
+
+	for events := range broadcaster.subscribers[event.Resource] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe subscribe events of the resource until cancel is called
+func (broadcaster *MemoryBroadcaster) Subscribe(resource string) (<-chan LiveEvent, func()) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	events := make(chan LiveEvent, 16)
+	if broadcaster.subscribers[resource] == nil {
+		broadcaster.subscribers[resource] = map[chan LiveEvent]bool{}
+	}
+	broadcaster.subscribers[resource][events] = true
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			broadcaster.mutex.Lock()
+			defer broadcaster.mutex.Unlock()
+
+			delete(broadcaster.subscribers[resource], events)
+			close(events)
+		})
+	}
+}
+
+// PostgresListener listen notifications of a Postgres channel, e.g: an adapter of lib/pq's Listener
+//
+//	type pqListener struct{ *pq.Listener }
+//
+//	func (l pqListener) Notifications() <-chan string {
+//		payloads := make(chan string)
+//		go func() {
+//			for n := range l.Notify {
+//				if n != nil {
+//					payloads <- n.Extra
+//				}
+//			}
+//		}()
+//		return payloads
+//	}
+type PostgresListener interface {
+	Listen(channel string) error
+	Notifications() <-chan string
+}
+
+// PostgresBroadcaster deliver live events across processes with Postgres LISTEN/NOTIFY, events are published with pg_notify, and received by Listener, e.g:
+//
+//	broadcaster := &admin.PostgresBroadcaster{
+//		Exec:     func(sql string, values ...interface{}) error { return db.Exec(sql, values...).Error },
+//		Listener: pqListener{listener},
+//	}
+//	go broadcaster.Run()
+//	Admin.Broadcaster = broadcaster
+type PostgresBroadcaster struct {
+	// Channel Postgres channel, default is qor_admin_live_events
+	Channel string
+	// Exec execute SQL to publish events with the database
+	Exec     func(sql string, values ...interface{}) error
+	Listener PostgresListener
+
+	local *MemoryBroadcaster
+	once  sync.Once
+}
+
+func (broadcaster *PostgresBroadcaster) init() {
+	broadcaster.once.Do(func() {
+		if broadcaster.Channel == "" {
+			broadcaster.Channel = "qor_admin_live_events"
+		}
+		broadcaster.local = NewMemoryBroadcaster()
+	})
+}
+
+// PostgresNotifyPayloadLimit max bytes of pg_notify payloads, Postgres rejects payloads of 8000 bytes or longer
+var PostgresNotifyPayloadLimit = 7999
+
+// Publish notify the event with pg_notify, it is delivered to subscribers by Run of all processes, events of many records are split into several notifications to fit PostgresNotifyPayloadLimit
+func (broadcaster *PostgresBroadcaster) Publish(event LiveEvent) error {
+	broadcaster.init()
+	payloads, err := liveEventPayloads(event, PostgresNotifyPayloadLimit)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range payloads {
+		if err := broadcaster.Exec("SELECT pg_notify(?, ?)", broadcaster.Channel, string(payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// liveEventPayloads encode the event to JSON payloads not longer than limit, records of the event are split into halves until their payloads fit
+func liveEventPayloads(event LiveEvent, limit int) ([][]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil || len(payload) <= limit {
+		return [][]byte{payload}, err
+	}
+
+	if len(event.RecordIDs) < 2 {
+		return nil, fmt.Errorf("payload of live event is longer than %v bytes", limit)
+	}
+
+	var payloads [][]byte
+	half := len(event.RecordIDs) / 2
+	for _, recordIDs := range [][]string{event.RecordIDs[:half], event.RecordIDs[half:]} {
+		part := event
+		part.RecordIDs = recordIDs
+
+		results, err := liveEventPayloads(part, limit)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, results...)
+	}
+	return payloads, nil
+}
+
+// Subscribe subscribe events of the resource received by Run
+func (broadcaster *PostgresBroadcaster) Subscribe(resource string) (<-chan LiveEvent, func()) {
+	broadcaster.init()
+	return broadcaster.local.Subscribe(resource)
+}
+
+// Run listen the channel and deliver received events to subscribers of current process until notifications are closed
+func (broadcaster *PostgresBroadcaster) Run() error {
+	broadcaster.init()
+	if err := broadcaster.Listener.Listen(broadcaster.Channel); err != nil {
+		return err
+	}
+
+	for payload := range broadcaster.Listener.Notifications() {
+		var event LiveEvent
+		if err := json.Unmarshal([]byte(payload), &event); err == nil {
+			broadcaster.local.Publish(event)
+		}
+	}
+	return nil
+}
+
+// liveEventsPublishedKey context key of records whose events are published in current request
+const liveEventsPublishedKey = "live_events_published"
+
+// publishLiveEvent publish event of resource's records, errors are ignored as live events are best effort
+func (context *Context) publishLiveEvent(res *Resource, typ string, recordIDs ...string) {
+	if res == nil || res.Config.Singleton || context.Admin == nil || context.Admin.Broadcaster == nil {
+		return
+	}
+
+	published, _ := context.Get(liveEventsPublishedKey).(map[string]bool)
+	if published == nil {
+		published = map[string]bool{}
+		context.Set(liveEventsPublishedKey, published)
+	}
+
+	event := LiveEvent{Type: typ, Resource: res.ToParam()}
+	for _, recordID := range recordIDs {
+		if recordID != "" {
+			event.RecordIDs = append(event.RecordIDs, recordID)
+			published[recordID] = true
+		}
+	}
+
+	if len(event.RecordIDs) == 0 {
+		return
+	}
+
+	if context.CurrentUser != nil {
+		event.UserID = fmt.Sprint(context.CurrentUser.GetID())
+		event.UserName = context.CurrentUser.DisplayName()
+	}
+	context.Admin.Broadcaster.Publish(event)
+}
+
+// unpublishedLiveEventIDs return record ids whose events haven't been published in current request, e.g: records deleted by an action don't need updated events
+func (context *Context) unpublishedLiveEventIDs(recordIDs []string) (ids []string) {
+	published, _ := context.Get(liveEventsPublishedKey).(map[string]bool)
+	for _, recordID := range recordIDs {
+		if !published[recordID] {
+			ids = append(ids, recordID)
+		}
+	}
+	return
+}
+
+// liveEventsURL return url of current resource's event stream
+func (context *Context) liveEventsURL() string {
+	if context.Resource == nil || context.Admin.Broadcaster == nil {
+		return ""
+	}
+	return path.Join(context.URLFor(context.Resource), "!events")
+}
+
+// writeLiveEvent write the event in Server-Sent Events format
+func writeLiveEvent(w io.Writer, event LiveEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %v\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+// LiveEvents stream created, updated, deleted events of the resource with Server-Sent Events
+func (ac *Controller) LiveEvents(context *Context) {
+	flusher, ok := context.Writer.(http.Flusher)
+	if !ok || ac.Admin.Broadcaster == nil {
+		http.Error(context.Writer, "live events are not supported", http.StatusNotImplemented)
+		return
+	}
+
+	header := context.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	context.Writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events, cancel := ac.Admin.Broadcaster.Subscribe(context.Resource.ToParam())
+	defer cancel()
+
+	ticker := time.NewTicker(LiveEventsKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-context.Request.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(context.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok || writeLiveEvent(context.Writer, event) != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryBroadcaster(t *testing.T) {
+	broadcaster := NewMemoryBroadcaster()
+	products, cancel := broadcaster.Subscribe("products")
+	orders, cancelOrders := broadcaster.Subscribe("orders")
+	defer cancelOrders()
+
+	broadcaster.Publish(LiveEvent{Type: LiveEventCreated, Resource: "products", RecordIDs: []string{"1"}})
+
+	select {
+	case event := <-products:
+		if event.Type != LiveEventCreated || len(event.RecordIDs) != 1 || event.RecordIDs[0] != "1" {
+			t.Errorf("unexpected event %#v", event)
+		}
+	default:
+		t.Errorf("subscriber of products should receive the event")
+	}
+
+	select {
+	case event := <-orders:
+		t.Errorf("subscriber of orders shouldn't receive event %#v", event)
+	default:
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-products; ok {
+		t.Errorf("events should be closed after cancel")
+	}
+
+	if err := broadcaster.Publish(LiveEvent{Type: LiveEventDeleted, Resource: "products", RecordIDs: []string{"1"}}); err != nil {
+		t.Errorf("publishing without subscribers should succeed, but got %v", err)
+	}
+}
+
+func TestMemoryBroadcasterSlowSubscriber(t *testing.T) {
+	broadcaster := NewMemoryBroadcaster()
+	_, cancel := broadcaster.Subscribe("products")
+	defer cancel()
+
+	for i := 0; i < 100; i++ {
+		broadcaster.Publish(LiveEvent{Type: LiveEventUpdated, Resource: "products", RecordIDs: []string{"1"}})
+	}
+}
+
+func TestWriteLiveEvent(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeLiveEvent(&buf, LiveEvent{Type: LiveEventUpdated, Resource: "products", RecordIDs: []string{"1", "2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "event: updated\ndata: {\"Type\":\"updated\",\"Resource\":\"products\",\"RecordIDs\":[\"1\",\"2\"]}\n\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestPostgresBroadcasterPublishLargeEvent(t *testing.T) {
+	var payloads []string
+	broadcaster := &PostgresBroadcaster{
+		Exec: func(sql string, values ...interface{}) error {
+			payloads = append(payloads, values[1].(string))
+			return nil
+		},
+	}
+
+	event := LiveEvent{Type: LiveEventDeleted, Resource: "products"}
+	for i := 0; i < 2000; i++ {
+		event.RecordIDs = append(event.RecordIDs, fmt.Sprint(100000+i))
+	}
+
+	if err := broadcaster.Publish(event); err != nil {
+		t.Fatal(err)
+	}
+
+	var recordIDs []string
+	for _, payload := range payloads {
+		if len(payload) > PostgresNotifyPayloadLimit {
+			t.Errorf("payload should be shorter than %v bytes, got %v", PostgresNotifyPayloadLimit, len(payload))
+		}
+
+		var received LiveEvent
+		if err := json.Unmarshal([]byte(payload), &received); err != nil || received.Type != event.Type || received.Resource != event.Resource {
+			t.Errorf("payload should be a part of the event, got %v", payload)
+		}
+		recordIDs = append(recordIDs, received.RecordIDs...)
+	}
+
+	if len(payloads) < 2 || fmt.Sprint(recordIDs) != fmt.Sprint(event.RecordIDs) {
+		t.Errorf("records should be split into several payloads in order, got %v payloads", len(payloads))
+	}
+}
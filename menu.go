@@ -25,6 +25,7 @@ func (admin *Admin) AddMenu(menu *Menu) *Menu {
 			old.Permissioner = menu.Permissioner
 			old.Permission = menu.Permission
 			old.RelativePath = menu.RelativePath
+			old.Badge = menu.Badge
 			*menu = *old
 			return old
 		}
@@ -53,6 +54,8 @@ type Menu struct {
 	Ancestors    []string
 	Permissioner HasPermissioner
 	Permission   *roles.Permission
+	// Badge return badge of the menu like count of records waiting for review, it is evaluated with current context and cached for MenuBadgeTTL, blank badge won't be shown
+	Badge func(context *Context) string
 
 	subMenus []*Menu
 	router   *Router
@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simonedbarber/roles"
+)
+
+// MenuBadgeTTL how long badges of menus are cached, badges are cached by menu, roles and current user
+var MenuBadgeTTL = 30 * time.Second
+
+type menuBadgeCacheItem struct {
+	Badge     string
+	ExpiredAt time.Time
+}
+
+// menuBadgeCache cache evaluated badges of menus
+var menuBadgeCache = struct {
+	sync.Mutex
+	items map[string]menuBadgeCacheItem
+}{items: map[string]menuBadgeCacheItem{}}
+
+// menuBadgeCacheKey return cache key of menu's badge for current user, badges might be different for users with different roles
+func (context *Context) menuBadgeCacheKey(m *Menu) string {
+	roles := append([]string{}, context.Roles...)
+	sort.Strings(roles)
+
+	key := fmt.Sprintf("%p:%v", m, strings.Join(roles, ","))
+	if context.CurrentUser != nil {
+		key += ":" + fmt.Sprint(context.CurrentUser.GetID())
+	}
+	return key
+}
+
+// menuBadge evaluate menu's badge with current context, return blank if the menu doesn't have a badge
+func (context *Context) menuBadge(m *Menu) (badge string) {
+	if m.Badge == nil {
+		return ""
+	}
+
+	key := context.menuBadgeCacheKey(m)
+
+	menuBadgeCache.Lock()
+	item, ok := menuBadgeCache.items[key]
+	menuBadgeCache.Unlock()
+
+	if ok && time.Now().Before(item.ExpiredAt) {
+		return item.Badge
+	}
+
+	defer func() {
+		// a broken badge shouldn't break the sidebar
+		if r := recover(); r != nil {
+			badge = ""
+		}
+	}()
+
+	ctx := context.clone()
+	ctx.Context = context.Context.Clone()
+	badge = m.Badge(ctx)
+
+	menuBadgeCache.Lock()
+	defer menuBadgeCache.Unlock()
+	for k, item := range menuBadgeCache.items {
+		if time.Now().After(item.ExpiredAt) {
+			delete(menuBadgeCache.items, k)
+		}
+	}
+	menuBadgeCache.items[key] = menuBadgeCacheItem{Badge: badge, ExpiredAt: time.Now().Add(MenuBadgeTTL)}
+	return badge
+}
+
+// resourceMenuBadge badge of resource's menu, evaluate the resource's Config.Badge with a context of the resource
+func (res *Resource) resourceMenuBadge(context *Context) string {
+	if res.Config.Badge == nil {
+		return ""
+	}
+	return res.Config.Badge(context.setResource(res))
+}
+
+// ScopeCountBadge return a badge shows count of records in the scope, blank if there is no record, e.g:
+//
+//	order.Config.Badge = order.ScopeCountBadge("Pending Review")
+func (res *Resource) ScopeCountBadge(name string) func(*Context) string {
+	return func(context *Context) string {
+		var scope *Scope
+		for _, s := range res.scopes {
+			if s.Name == name {
+				scope = s
+			}
+		}
+
+		if scope == nil || !res.HasPermission(roles.Read, context.Context) {
+			return ""
+		}
+
+		// count with a clean request of the resource's index page, so params of current page like keyword, order_by don't affect it
+		request, err := http.NewRequest("GET", context.URLFor(res), nil)
+		if err != nil {
+			return ""
+		}
+		request.Form = url.Values{}
+
+		ctx := context.clone()
+		ctx.Context = context.Context.Clone()
+		ctx.Settings = map[string]interface{}{}
+		ctx.Request = request
+
+		var (
+			count    int64
+			searcher = &Searcher{Context: ctx, scopes: []*Scope{scope}}
+			qorCtx   = searcher.filterData(ctx.Context.Clone(), true)
+		)
+
+		if err := qorCtx.GetDB().Model(res.Value).Count(&count).Error; err != nil || count == 0 {
+			return ""
+		}
+		return formatCount(count)
+	}
+}
@@ -120,3 +120,32 @@ func TestMenuPriority(t *testing.T) {
 		}
 	}
 }
+
+func TestMenuBadge(t *testing.T) {
+	var evaluated int
+	menu := &Menu{Name: "Orders", Badge: func(context *Context) string {
+		evaluated++
+		return "12"
+	}}
+
+	adminContext := &Context{Context: &qor.Context{Roles: []string{"admin"}}}
+	for i := 0; i < 2; i++ {
+		if badge := adminContext.menuBadge(menu); badge != "12" {
+			t.Errorf("menu badge should be 12, but got %v", badge)
+		}
+	}
+
+	if evaluated != 1 {
+		t.Errorf("menu badge should be cached, but evaluated %v times", evaluated)
+	}
+
+	editorContext := &Context{Context: &qor.Context{Roles: []string{"editor"}}}
+	editorContext.menuBadge(menu)
+	if evaluated != 2 {
+		t.Errorf("menu badge should be evaluated for different roles")
+	}
+
+	if badge := adminContext.menuBadge(&Menu{Name: "Products"}); badge != "" {
+		t.Errorf("menu without badge should have blank badge, but got %v", badge)
+	}
+}
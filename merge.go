@@ -0,0 +1,490 @@
+package admin
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MergeActionName name of the batch action opens merge page
+const MergeActionName = "Merge"
+
+// MergeConfig merge configuration of a resource, refer Resource.UseMerge
+type MergeConfig struct {
+	// Permission permission to merge records, it is checked with roles.Update, resource's update and delete permissions are required as well
+	Permission *roles.Permission
+	// ArchiveHandler archive merged records instead of deleting them, e.g: mark them as merged into the survivor
+	ArchiveHandler func(loser, survivor interface{}, context *Context) error
+	// References models referencing records of the resource that aren't admin resources or their relations, their foreign keys are moved to the survivor as well, e.g: []interface{}{&LoyaltyCard{}}
+	References []interface{}
+}
+
+// UseMerge add a batch action Merge to resource, which merges selected records into one of them, e.g:
+//
+//	customer.UseMerge(&admin.MergeConfig{
+//		Permission: roles.Allow(roles.Update, "admin"),
+//	})
+//
+// The merge page lists EditAttrs of selected records side by side to choose value of each attribute, records referencing merged records with gorm relationships are moved to the survivor, then merged records are deleted or archived with ArchiveHandler, all in a transaction with an audit entry
+func (res *Resource) UseMerge(config *MergeConfig) {
+	if config == nil {
+		config = &MergeConfig{}
+	}
+
+	if res.Config.Singleton {
+		utils.ExitWithMsg("singleton resource %v couldn't merge records", res.Name)
+	}
+	res.merge = config
+
+	res.Action(&Action{
+		Name:        MergeActionName,
+		Method:      "GET",
+		URLOpenType: "slideout",
+		Modes:       []string{"batch"},
+		URL: func(record interface{}, context *Context) string {
+			return path.Join(context.URLFor(res), "!merge")
+		},
+		Visible: func(record interface{}, context *Context) bool {
+			return res.canMerge(context)
+		},
+	})
+
+	controller := &Controller{Admin: res.GetAdmin()}
+	res.RegisterRoute("GET", "/!merge", controller.Merge, &RouteConfig{PermissionMode: roles.Update})
+	res.RegisterRoute("POST", "/!merge", controller.Merge, &RouteConfig{PermissionMode: roles.Update})
+}
+
+// canMerge check if current user could merge records of the resource
+func (res *Resource) canMerge(context *Context) bool {
+	if res.merge == nil || !res.HasPermission(roles.Update, context.Context) || !res.HasPermission(roles.Delete, context.Context) {
+		return false
+	}
+
+	if res.merge.Permission != nil {
+		var userRoles []interface{}
+		for _, role := range context.Roles {
+			userRoles = append(userRoles, role)
+		}
+		return res.merge.Permission.HasPermission(roles.Update, userRoles...)
+	}
+	return true
+}
+
+// mergeMetas metas of EditAttrs could be chosen when merging records, they are attributes or belongs to relations of the model, has many and many to many relations are merged by moving records
+func (res *Resource) mergeMetas(context *Context) (metas []*Meta) {
+	scope := utils.NewScope(res.Value)
+	for _, meta := range res.ConvertSectionToMetas(res.allowedSections(res.EditAttrs(), context, roles.Update)) {
+		if len(mergeFields(scope, meta.FieldName)) > 0 {
+			metas = append(metas, meta)
+		}
+	}
+	return
+}
+
+// mergeFields return fields copied from the chosen record for a meta, foreign keys are copied with belongs to relations
+func mergeFields(scope *schema.Schema, fieldName string) (fields []*schema.Field) {
+	if fieldName == "" {
+		return nil
+	}
+
+	if relationship, ok := scope.Relationships.Relations[fieldName]; ok {
+		if relationship.Type != schema.BelongsTo {
+			return nil
+		}
+
+		fields = append(fields, relationship.Field)
+		for _, reference := range relationship.References {
+			if reference.ForeignKey.Schema == scope {
+				fields = append(fields, reference.ForeignKey)
+			}
+		}
+		return fields
+	}
+
+	if field := scope.LookUpField(fieldName); field != nil && field.DBName != "" && !field.PrimaryKey &&
+		field.AutoCreateTime == 0 && field.AutoUpdateTime == 0 && field.FieldType != reflect.TypeOf(gorm.DeletedAt{}) {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// parseMergeChoices parse survivor and chosen record of each attribute from form, e.g:
+//
+//	survivor=1&fields[Name]=2&fields[Email]=1
+//
+// attributes not chosen use survivor's values
+func parseMergeChoices(form url.Values, primaryValues []string, metas []string) (survivor string, choices map[string]string, err error) {
+	if len(primaryValues) < 2 {
+		return "", nil, errors.New("please select at least two records to merge")
+	}
+
+	if survivor = form.Get("survivor"); survivor == "" {
+		survivor = primaryValues[0]
+	}
+
+	if !isContainsColumn(primaryValues, survivor) {
+		return "", nil, fmt.Errorf("record %v isn't selected", survivor)
+	}
+
+	choices = map[string]string{}
+	for _, meta := range metas {
+		choice := form.Get(fmt.Sprintf("fields[%v]", meta))
+		if choice == "" {
+			choice = survivor
+		} else if !isContainsColumn(primaryValues, choice) {
+			return "", nil, fmt.Errorf("record %v isn't selected", choice)
+		}
+		choices[meta] = choice
+	}
+	return survivor, choices, nil
+}
+
+// mergeSchemas return schemas might reference records of the resource, which are models of admin's resources, models of their relations recursively, and models of MergeConfig's References
+func (res *Resource) mergeSchemas() (schemas []*schema.Schema) {
+	var (
+		visited = map[reflect.Type]bool{}
+		visit   func(s *schema.Schema)
+	)
+
+	visit = func(s *schema.Schema) {
+		if s == nil || visited[s.ModelType] {
+			return
+		}
+		visited[s.ModelType] = true
+		schemas = append(schemas, s)
+
+		for _, relationship := range s.Relationships.Relations {
+			visit(relationship.FieldSchema)
+		}
+	}
+
+	for _, r := range res.GetAdmin().GetResources() {
+		if r.Value != nil {
+			visit(utils.NewScope(r.Value))
+		}
+	}
+
+	if res.merge != nil {
+		for _, model := range res.merge.References {
+			visit(utils.NewScope(model))
+		}
+	}
+	return schemas
+}
+
+// mergeReference a foreign key that references records of a model
+type mergeReference struct {
+	// Table table has the foreign key, join table for many to many relations
+	Table string
+	// JoinTable the foreign key is in a many to many relation's join table
+	JoinTable bool
+	// ForeignKey column name of the foreign key
+	ForeignKey string
+	// PrimaryKey referenced field of the model
+	PrimaryKey *schema.Field
+	// Conditions additional conditions of the relation, e.g: type of polymorphic relations
+	Conditions map[string]interface{}
+	// LinkKeys columns of join table reference the other side of many to many relations, links are duplicated if they have same foreign key and link keys
+	LinkKeys []string
+}
+
+// mergeReferences collect foreign keys reference modelType from relationships of schemas, has one, has many, many to many relations of the model itself and belongs to, many to many relations of other models are included, duplicates are removed
+func mergeReferences(modelType reflect.Type, schemas []*schema.Schema) (references []mergeReference) {
+	keys := map[string]bool{}
+	add := func(relationship *schema.Relationship, ownPrimaryKey bool) {
+		var (
+			reference  = mergeReference{Table: relationship.FieldSchema.Table, Conditions: map[string]interface{}{}}
+			foreignKey []*schema.Reference
+		)
+
+		if relationship.JoinTable != nil {
+			reference.Table, reference.JoinTable = relationship.JoinTable.Table, true
+		} else if relationship.Type == schema.BelongsTo {
+			reference.Table = relationship.Schema.Table
+		}
+
+		for _, r := range relationship.References {
+			if r.PrimaryKey == nil {
+				reference.Conditions[r.ForeignKey.DBName] = r.PrimaryValue
+			} else if r.OwnPrimaryKey == ownPrimaryKey {
+				foreignKey = append(foreignKey, r)
+			} else if relationship.JoinTable != nil {
+				reference.LinkKeys = append(reference.LinkKeys, r.ForeignKey.DBName)
+			}
+		}
+
+		for _, r := range foreignKey {
+			ref := reference
+			ref.ForeignKey, ref.PrimaryKey = r.ForeignKey.DBName, r.PrimaryKey
+			if key := ref.Table + "." + ref.ForeignKey; !keys[key] {
+				keys[key] = true
+				references = append(references, ref)
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		if s.ModelType == modelType {
+			for _, relationship := range s.Relationships.HasOne {
+				add(relationship, true)
+			}
+			for _, relationship := range s.Relationships.HasMany {
+				add(relationship, true)
+			}
+			for _, relationship := range s.Relationships.Many2Many {
+				add(relationship, true)
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		for _, relationship := range s.Relationships.BelongsTo {
+			if relationship.FieldSchema.ModelType == modelType {
+				add(relationship, false)
+			}
+		}
+
+		if s.ModelType != modelType {
+			for _, relationship := range s.Relationships.Many2Many {
+				if relationship.FieldSchema.ModelType == modelType {
+					add(relationship, false)
+				}
+			}
+		}
+	}
+	return references
+}
+
+// repoint move records reference losers to survivor
+func (reference mergeReference) repoint(tx *gorm.DB, survivor interface{}, losers []interface{}) error {
+	var (
+		ctx              = stdcontext.Background()
+		loserValues      []interface{}
+		survivorValue, _ = reference.PrimaryKey.ValueOf(ctx, reflect.Indirect(reflect.ValueOf(survivor)))
+		where            = func(db *gorm.DB) *gorm.DB {
+			db = db.Table(reference.Table).Where(fmt.Sprintf("%v IN ?", reference.ForeignKey), loserValues)
+			for column, value := range reference.Conditions {
+				db = db.Where(fmt.Sprintf("%v = ?", column), value)
+			}
+			return db
+		}
+	)
+
+	for _, loser := range losers {
+		if value, zero := reference.PrimaryKey.ValueOf(ctx, reflect.Indirect(reflect.ValueOf(loser))); !zero {
+			loserValues = append(loserValues, value)
+		}
+	}
+
+	if len(loserValues) == 0 {
+		return nil
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true})
+	if !reference.JoinTable {
+		return where(db).UpdateColumn(reference.ForeignKey, survivorValue).Error
+	}
+
+	// links are moved to survivor in place to keep other columns of join table, e.g: surrogate ids, links would be duplicated with survivor's or other losers' are deleted
+	var (
+		rows   []map[string]interface{}
+		linked = map[string]bool{}
+		linkOf = func(row map[string]interface{}) string {
+			var values []string
+			for _, key := range reference.LinkKeys {
+				values = append(values, fmt.Sprint(row[key]))
+			}
+			return strings.Join(values, "\x00")
+		}
+		rowDB = func(db *gorm.DB, row map[string]interface{}) *gorm.DB {
+			db = db.Table(reference.Table).Where(fmt.Sprintf("%v = ?", reference.ForeignKey), row[reference.ForeignKey])
+			for _, key := range reference.LinkKeys {
+				db = db.Where(fmt.Sprintf("%v = ?", key), row[key])
+			}
+			for column, value := range reference.Conditions {
+				db = db.Where(fmt.Sprintf("%v = ?", column), value)
+			}
+			return db
+		}
+	)
+
+	survivorDB := db.Table(reference.Table).Where(fmt.Sprintf("%v = ?", reference.ForeignKey), survivorValue)
+	for column, value := range reference.Conditions {
+		survivorDB = survivorDB.Where(fmt.Sprintf("%v = ?", column), value)
+	}
+
+	if err := survivorDB.Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		linked[linkOf(row)] = true
+	}
+
+	rows = nil
+	if err := where(db).Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var err error
+		if link := linkOf(row); linked[link] {
+			err = rowDB(db, row).Delete(map[string]interface{}{}).Error
+		} else {
+			linked[link] = true
+			err = rowDB(db, row).UpdateColumn(reference.ForeignKey, survivorValue).Error
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeRecords merge records with primary values into survivor in a transaction, choices are primary values of records chosen for each meta, return the merged survivor
+func (res *Resource) MergeRecords(survivorID string, primaryValues []string, choices map[string]string, context *Context) (survivor interface{}, err error) {
+	var merged []interface{}
+	if !isContainsColumn(primaryValues, survivorID) {
+		return nil, fmt.Errorf("record %v isn't selected", survivorID)
+	}
+
+	// the survivor is updated and losers are deleted, none of them could be locked by others
+	if err := context.checkEditLocks(primaryValues...); err != nil {
+		return nil, err
+	}
+
+	err = context.GetDB().Transaction(func(tx *gorm.DB) error {
+		ctx := context.clone()
+		ctx.Context = context.Context.Clone()
+		ctx.SetDB(tx)
+
+		records, err := res.findDeletingRecords(primaryValues, ctx.Context)
+		if err != nil {
+			return err
+		}
+
+		var (
+			scope     = utils.NewScope(res.Value)
+			recordsBy = map[string]interface{}{}
+			losers    []interface{}
+			loserIDs  []string
+			stdctx    = stdcontext.Background()
+		)
+
+		for idx, record := range records {
+			recordsBy[primaryValues[idx]] = record
+			if primaryValues[idx] != survivorID {
+				losers = append(losers, record)
+				loserIDs = append(loserIDs, primaryValues[idx])
+			}
+		}
+		survivor = recordsBy[survivorID]
+		merged = losers
+
+		survivorCtx := ctx.Context.Clone()
+		survivorCtx.ResourceID = survivorID
+		old, err := res.findOldRecord(survivorCtx, HookBeforeUpdate, HookAfterUpdate)
+		if err != nil {
+			return err
+		}
+
+		for _, meta := range res.mergeMetas(ctx) {
+			chosen, ok := recordsBy[choices[meta.Name]]
+			if !ok || chosen == survivor {
+				continue
+			}
+
+			for _, field := range mergeFields(scope, meta.FieldName) {
+				value := field.ReflectValueOf(stdctx, reflect.Indirect(reflect.ValueOf(chosen)))
+				if err := field.Set(stdctx, reflect.Indirect(reflect.ValueOf(survivor)), value.Interface()); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, reference := range mergeReferences(scope.ModelType, res.mergeSchemas()) {
+			if err := reference.repoint(tx, survivor, losers); err != nil {
+				return err
+			}
+		}
+
+		for idx, loser := range losers {
+			if res.merge != nil && res.merge.ArchiveHandler != nil {
+				if err := res.merge.ArchiveHandler(loser, survivor, ctx); err != nil {
+					return err
+				}
+				continue
+			}
+
+			argument := &HookArgument{Context: ctx, Old: loser, Tx: tx}
+			if err := res.callHooks(HookBeforeDelete, argument); err != nil {
+				return err
+			}
+
+			qorCtx := ctx.Context.Clone()
+			qorCtx.ResourceID = loserIDs[idx]
+			if err := res.CallDelete(res.NewStruct(), qorCtx); err != nil {
+				return err
+			}
+
+			if err := res.callHooks(HookAfterDelete, argument); err != nil {
+				return err
+			}
+		}
+
+		// save survivor after removing losers, so values of unique fields could be taken from losers
+		if err := res.updateRecord(old, survivor, ctx, ctx.Context); err != nil {
+			return err
+		}
+
+		return ctx.addAuditLog(tx, "merge", res, survivorID, map[string]interface{}{"Merged": loserIDs, "Choices": choices})
+	})
+
+	if err == nil {
+		var loserIDs []string
+		for _, primaryValue := range primaryValues {
+			if primaryValue != survivorID {
+				loserIDs = append(loserIDs, primaryValue)
+			}
+		}
+
+		// archived losers are kept, they are updated by ArchiveHandler
+		if res.merge != nil && res.merge.ArchiveHandler != nil {
+			context.publishLiveEvent(res, LiveEventUpdated, append(loserIDs, survivorID)...)
+			context.triggerWebhooks(res, LiveEventUpdated, append(merged, survivor)...)
+		} else {
+			context.publishLiveEvent(res, LiveEventDeleted, loserIDs...)
+			context.publishLiveEvent(res, LiveEventUpdated, survivorID)
+			context.triggerWebhooks(res, LiveEventDeleted, merged...)
+			context.triggerWebhooks(res, LiveEventUpdated, survivor)
+		}
+	}
+	return survivor, err
+}
+
+// mergeView data of merge page
+type mergeView struct {
+	Records       []interface{}
+	PrimaryValues []string
+	Metas         []*Meta
+	Survivor      string
+	Choices       map[string]string
+}
+
+// IsChosen check if the record's value of meta is chosen
+func (view mergeView) IsChosen(meta *Meta, primaryValue string) bool {
+	if choice, ok := view.Choices[meta.Name]; ok {
+		return choice == primaryValue
+	}
+	return primaryValue == view.Survivor
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type mergeCustomer struct {
+	gorm.Model
+	Name      string
+	Addresses []mergeAddress
+	Tags      []mergeTag `gorm:"many2many:merge_customer_tags;"`
+}
+
+type mergeAddress struct {
+	gorm.Model
+	MergeCustomerID uint
+}
+
+type mergeTag struct {
+	gorm.Model
+}
+
+type mergeOrder struct {
+	gorm.Model
+	MergeCustomerID uint
+	MergeCustomer   mergeCustomer
+}
+
+func TestParseMergeChoices(t *testing.T) {
+	form := url.Values{"survivor": {"2"}, "fields[Name]": {"1"}}
+	survivor, choices, err := parseMergeChoices(form, []string{"1", "2"}, []string{"Name", "Email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if survivor != "2" || !reflect.DeepEqual(choices, map[string]string{"Name": "1", "Email": "2"}) {
+		t.Errorf("wrong merge choices, got %v %v", survivor, choices)
+	}
+
+	if _, _, err := parseMergeChoices(url.Values{"fields[Name]": {"3"}}, []string{"1", "2"}, []string{"Name"}); err == nil {
+		t.Errorf("should not choose records not selected")
+	}
+
+	if _, _, err := parseMergeChoices(url.Values{}, []string{"1"}, []string{"Name"}); err == nil {
+		t.Errorf("should not merge a single record")
+	}
+}
+
+func TestMergeReferences(t *testing.T) {
+	var schemas []*schema.Schema
+	cache := &sync.Map{}
+	for _, value := range []interface{}{&mergeCustomer{}, &mergeAddress{}, &mergeOrder{}} {
+		s, err := schema.Parse(value, cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemas = append(schemas, s)
+	}
+
+	var results []string
+	for _, reference := range mergeReferences(schemas[0].ModelType, schemas) {
+		results = append(results, reference.Table+"."+reference.ForeignKey)
+		if reference.PrimaryKey.Name != "ID" {
+			t.Errorf("reference %v should reference ID, got %v", reference.Table, reference.PrimaryKey.Name)
+		}
+
+		if reference.JoinTable && !reflect.DeepEqual(reference.LinkKeys, []string{"merge_tag_id"}) {
+			t.Errorf("links of join table %v should be keyed with merge_tag_id, got %v", reference.Table, reference.LinkKeys)
+		}
+	}
+
+	if !reflect.DeepEqual(results, []string{"merge_addresses.merge_customer_id", "merge_customer_tags.merge_customer_id", "merge_orders.merge_customer_id"}) {
+		t.Errorf("wrong merge references, got %v", results)
+	}
+}
@@ -57,20 +57,44 @@ func (datetimeConfig *DatetimeConfig) ConfigureQorMeta(metaor resource.Metaor) {
 	}
 }
 
-// ConfigureQORAdminFilter configure admin filter for datetime
+// ConfigureQORAdminFilter configure admin filter for datetime, Start, End are inclusive, and could be presets or relative times like `-30d`, refer ParseDatetimeRange for details
+//
+//	filters[CreatedAt].Start=2024-01&filters[CreatedAt].End=2024-03  // from 2024-01-01 to the end of 2024-03
+//	filters[CreatedAt].Preset=last_7_days                             // evaluated when filtering, so saved filters stay relative
 func (datetimeConfig *DatetimeConfig) ConfigureQORAdminFilter(filter *Filter) {
 	if filter.Handler == nil {
 		if dbName := filter.Resource.GetMeta(filter.Name).DBName(); dbName != "" {
 			filter.Handler = func(tx *gorm.DB, filterArgument *FilterArgument) *gorm.DB {
+				var start, end string
 				if metaValue := filterArgument.Value.Get("Start"); metaValue != nil {
-					if start := utils.ToString(metaValue.Value); start != "" {
-						tx = tx.Where(fmt.Sprintf("%v > ?", dbName), start)
-					}
+					start = utils.ToString(metaValue.Value)
 				}
 
 				if metaValue := filterArgument.Value.Get("End"); metaValue != nil {
-					if end := utils.ToString(metaValue.Value); end != "" {
-						tx = tx.Where(fmt.Sprintf("%v < ?", dbName), end)
+					end = utils.ToString(metaValue.Value)
+				}
+
+				if metaValue := filterArgument.Value.Get("Preset"); metaValue != nil {
+					if preset := utils.ToString(metaValue.Value); preset != "" {
+						start, end = preset, preset
+					}
+				}
+
+				if start != "" {
+					if timeRange, err := ParseDatetimeRange(start, filterArgument.Context); err == nil {
+						tx = tx.Where(fmt.Sprintf("%v >= ?", dbName), timeRange.Start)
+					} else {
+						filterArgument.Context.AddError(err)
+					}
+				}
+
+				if end != "" {
+					if timeRange, err := ParseDatetimeRange(end, filterArgument.Context); err != nil {
+						filterArgument.Context.AddError(err)
+					} else if timeRange.IsMoment() {
+						tx = tx.Where(fmt.Sprintf("%v <= ?", dbName), timeRange.End)
+					} else {
+						tx = tx.Where(fmt.Sprintf("%v < ?", dbName), timeRange.End)
 					}
 				}
 
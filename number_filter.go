@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// numberSeparatorReplacer remove digit group separators from a number, e.g: 1,234.50, 1 234.50, 1_234.50
+var numberSeparatorReplacer = strings.NewReplacer(",", "", " ", "", "_", "")
+
+// numberGroupsRegexp match numbers with digits grouped by 3, separators could be ",", " " or "_", decimal mark is "."
+var numberGroupsRegexp = regexp.MustCompile(`^[+-]?\d{1,3}([, _]\d{3})+(\.\d*)?$`)
+
+// normalizeNumber remove digit group separators from a number, separators are only accepted between groups of 3 digits,
+// so decimal commas aren't taken as separators, e.g: 1,5 is rejected instead of being parsed as 15
+func normalizeNumber(str string) (string, error) {
+	if !strings.ContainsAny(str, ", _") {
+		return str, nil
+	}
+
+	if !numberGroupsRegexp.MatchString(str) {
+		return "", fmt.Errorf("%q isn't a number, use . as decimal mark, digit groups could be separated with , space or _", str)
+	}
+	return numberSeparatorReplacer.Replace(str), nil
+}
+
+// numberParser return a function that parse string to value of the number type, return nil if the type isn't a number, supports ints, uints, floats, sql.NullInt64, sql.NullInt32, sql.NullFloat64, decimal.Decimal and decimal.NullDecimal
+func numberParser(typ reflect.Type) func(string) (interface{}, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var parse func(string) (interface{}, error)
+	switch reflect.New(typ).Elem().Interface().(type) {
+	case decimal.Decimal, decimal.NullDecimal:
+		parse = func(str string) (interface{}, error) {
+			return decimal.NewFromString(str)
+		}
+	case sql.NullInt64, sql.NullInt32:
+		typ = reflect.TypeOf(int64(0))
+	case sql.NullFloat64:
+		typ = reflect.TypeOf(float64(0))
+	}
+
+	if parse == nil {
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			parse = func(str string) (interface{}, error) {
+				return strconv.ParseInt(str, 10, 64)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			parse = func(str string) (interface{}, error) {
+				return strconv.ParseUint(str, 10, 64)
+			}
+		case reflect.Float32, reflect.Float64:
+			parse = func(str string) (interface{}, error) {
+				return strconv.ParseFloat(str, 64)
+			}
+		default:
+			return nil
+		}
+	}
+
+	return func(str string) (interface{}, error) {
+		str, err := normalizeNumber(str)
+		if err != nil {
+			return nil, err
+		}
+		return parse(str)
+	}
+}
+
+// numberCondition build sql condition for number column with operation, keyword of `between` is a range like `10..100`, `10..` or `..100`, return false if keyword is not a valid number for the operation
+func numberCondition(column, operation, keyword string, parse func(string) (interface{}, error)) (string, []interface{}, bool) {
+	switch operation {
+	case "present":
+		return fmt.Sprintf("%v IS NOT NULL", column), nil, true
+	case "blank":
+		return fmt.Sprintf("%v IS NULL", column), nil, true
+	case "between":
+		var (
+			conditions []string
+			values     []interface{}
+			bounds     = strings.SplitN(keyword, "..", 2)
+		)
+
+		if len(bounds) != 2 {
+			return "", nil, false
+		}
+
+		for idx, operator := range []string{">=", "<="} {
+			if bound := strings.TrimSpace(bounds[idx]); bound != "" {
+				value, err := parse(bound)
+				if err != nil {
+					return "", nil, false
+				}
+				conditions = append(conditions, fmt.Sprintf("%v %v ?", column, operator))
+				values = append(values, value)
+			}
+		}
+
+		if len(conditions) == 0 {
+			return "", nil, false
+		}
+		return "(" + strings.Join(conditions, " AND ") + ")", values, true
+	}
+
+	value, err := parse(strings.TrimSpace(keyword))
+	if err != nil {
+		return "", nil, false
+	}
+
+	operator := "="
+	switch operation {
+	case "ne":
+		operator = "<>"
+	case "gt":
+		operator = ">"
+	case "gteq", "gte":
+		operator = ">="
+	case "lt":
+		operator = "<"
+	case "lteq", "lte":
+		operator = "<="
+	}
+	return fmt.Sprintf("%v %v ?", column, operator), []interface{}{value}, true
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNumberCondition(t *testing.T) {
+	type testCase struct {
+		Value     interface{}
+		Operation string
+		Keyword   string
+		Condition string
+		Values    []interface{}
+		Invalid   bool
+	}
+
+	price := decimal.RequireFromString("1234.5")
+	testCases := []testCase{
+		{Value: 0, Operation: "eq", Keyword: "12", Condition: "price = ?", Values: []interface{}{int64(12)}},
+		{Value: 0, Operation: "ne", Keyword: "12", Condition: "price <> ?", Values: []interface{}{int64(12)}},
+		{Value: uint(0), Operation: "gteq", Keyword: "1,000", Condition: "price >= ?", Values: []interface{}{uint64(1000)}},
+		{Value: 0.0, Operation: "lteq", Keyword: "9.99", Condition: "price <= ?", Values: []interface{}{9.99}},
+		{Value: sql.NullFloat64{}, Operation: "between", Keyword: "1.5..9.5", Condition: "(price >= ? AND price <= ?)", Values: []interface{}{1.5, 9.5}},
+		{Value: sql.NullInt64{}, Operation: "between", Keyword: "..100", Condition: "(price <= ?)", Values: []interface{}{int64(100)}},
+		{Value: decimal.Decimal{}, Operation: "gt", Keyword: "1 234.50", Condition: "price > ?", Values: []interface{}{price}},
+		{Value: &decimal.NullDecimal{}, Operation: "between", Keyword: "1234.5..", Condition: "(price >= ?)", Values: []interface{}{price}},
+		{Value: 0, Operation: "present", Keyword: "", Condition: "price IS NOT NULL"},
+		{Value: 0, Operation: "eq", Keyword: "abc", Invalid: true},
+		{Value: 0.0, Operation: "eq", Keyword: "1,5", Invalid: true},
+		{Value: decimal.Decimal{}, Operation: "eq", Keyword: "1.234,5", Invalid: true},
+		{Value: 0, Operation: "eq", Keyword: "12,34", Invalid: true},
+		{Value: 0, Operation: "lt", Keyword: "-1_000_000", Condition: "price < ?", Values: []interface{}{int64(-1000000)}},
+		{Value: 0, Operation: "between", Keyword: "..", Invalid: true},
+		{Value: 0.0, Operation: "between", Keyword: "1..x", Invalid: true},
+	}
+
+	for _, testCase := range testCases {
+		parse := numberParser(reflect.TypeOf(testCase.Value))
+		if parse == nil {
+			t.Errorf("%T should be parsed as number", testCase.Value)
+			continue
+		}
+
+		condition, values, ok := numberCondition("price", testCase.Operation, testCase.Keyword, parse)
+		if ok == testCase.Invalid {
+			t.Errorf("%v %v: expect valid %v, got %v", testCase.Operation, testCase.Keyword, !testCase.Invalid, ok)
+			continue
+		}
+
+		if !testCase.Invalid {
+			if condition != testCase.Condition {
+				t.Errorf("%v %v: expect condition %v, got %v", testCase.Operation, testCase.Keyword, testCase.Condition, condition)
+			}
+
+			if len(values) != len(testCase.Values) {
+				t.Errorf("%v %v: expect values %v, got %v", testCase.Operation, testCase.Keyword, testCase.Values, values)
+				continue
+			}
+
+			for idx, value := range values {
+				if d, ok := value.(decimal.Decimal); ok {
+					if !d.Equal(testCase.Values[idx].(decimal.Decimal)) {
+						t.Errorf("%v %v: expect value %v, got %v", testCase.Operation, testCase.Keyword, testCase.Values[idx], value)
+					}
+				} else if value != testCase.Values[idx] {
+					t.Errorf("%v %v: expect value %#v, got %#v", testCase.Operation, testCase.Keyword, testCase.Values[idx], value)
+				}
+			}
+		}
+	}
+
+	if numberParser(reflect.TypeOf("")) != nil {
+		t.Errorf("string should not be parsed as number")
+	}
+}
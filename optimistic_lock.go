@@ -0,0 +1,244 @@
+package admin
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// VersionParam form param of record's version when it was opened for editing
+const VersionParam = "_version"
+
+// VersionConflictKey context key of differences between current record and submitted values when the update is stale
+const VersionConflictKey = "version_conflict"
+
+var (
+	// ErrVersionConflict the record has been changed since it was opened for editing
+	ErrVersionConflict = errors.New("record has been changed by someone else since you opened it")
+	// ErrVersionRequired updates without version are rejected when OptimisticLockConfig.Required is true
+	ErrVersionRequired = errors.New("version of the record is required to update it")
+)
+
+// OptimisticLockConfig optimistic lock configuration of a resource, refer Resource.UseOptimisticLock
+type OptimisticLockConfig struct {
+	// Field version field of the model, an integer field increased on each update, or a time field like UpdatedAt, default is field Version if it exists, otherwise UpdatedAt
+	Field string
+	// Required reject updates without version with 428 Precondition Required, by default they are saved without checking
+	Required bool
+}
+
+// UseOptimisticLock reject stale updates of resource's records with 409 Conflict, e.g:
+//
+//	order.UseOptimisticLock(&admin.OptimisticLockConfig{Field: "LockVersion"})
+//
+// Edit form carries record's version with param _version, responses of Show, Edit, Update have header ETag, API clients could send the version back with header If-Match
+func (res *Resource) UseOptimisticLock(config *OptimisticLockConfig) {
+	if config == nil {
+		config = &OptimisticLockConfig{}
+	}
+
+	scope := utils.NewScope(res.Value)
+	if config.Field == "" {
+		config.Field = "UpdatedAt"
+		if scope.LookUpField("Version") != nil {
+			config.Field = "Version"
+		}
+	}
+
+	if field := scope.LookUpField(config.Field); field == nil || field.DBName == "" {
+		utils.ExitWithMsg("resource %v's model doesn't have version field %v", res.Name, config.Field)
+	}
+	res.optimisticLock = config
+}
+
+// versionField return version field of the resource, nil if optimistic lock isn't used
+func (res *Resource) versionField() *schema.Field {
+	if res.optimisticLock == nil {
+		return nil
+	}
+	return utils.NewScope(res.Value).LookUpField(res.optimisticLock.Field)
+}
+
+// versionValue return raw value of record's version field
+func (res *Resource) versionValue(record interface{}) interface{} {
+	field := res.versionField()
+	if field == nil || record == nil {
+		return nil
+	}
+
+	value, _ := field.ValueOf(stdcontext.Background(), reflect.Indirect(reflect.ValueOf(record)))
+	return value
+}
+
+// RecordVersion return version of the record used in edit form and ETag, blank if optimistic lock isn't used
+func (res *Resource) RecordVersion(record interface{}) string {
+	if res.versionField() == nil {
+		return ""
+	}
+	return versionString(res.versionValue(record))
+}
+
+// versionTimeLayout layout of time versions, milliseconds are kept as gorm saves times with milliseconds by default
+const versionTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// versionString convert value of version field to string, times are converted to UTC with milliseconds
+func versionString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(versionTimeLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(versionTimeLayout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// versionETag return ETag of the version
+func versionETag(version string) string {
+	return strconv.Quote(version)
+}
+
+// parseIfMatch parse versions from header If-Match, e.g: `"3"`, `W/"3", "4"`, `*` matches any version
+func parseIfMatch(header string) (versions []string) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "" {
+			continue
+		}
+
+		if version, err := strconv.Unquote(tag); err == nil {
+			tag = version
+		}
+		versions = append(versions, tag)
+	}
+	return
+}
+
+// requestVersions return versions sent with request, from header If-Match or param _version
+func requestVersions(request *http.Request) []string {
+	if versions := parseIfMatch(request.Header.Get("If-Match")); len(versions) > 0 {
+		return versions
+	}
+
+	if version := request.Form.Get(VersionParam); version != "" {
+		return []string{version}
+	}
+	return nil
+}
+
+// setVersionHeader set header ETag with record's version
+func (context *Context) setVersionHeader(record interface{}) {
+	if res := context.Resource; res != nil && res.versionField() != nil && record != nil {
+		context.Writer.Header().Set("ETag", versionETag(res.RecordVersion(record)))
+	}
+}
+
+// checkVersion lock current record in context's transaction and compare its version with the version sent with request, return the current record and ErrVersionConflict if it is stale
+func (res *Resource) checkVersion(context *Context) (interface{}, error) {
+	if res.versionField() == nil {
+		return nil, nil
+	}
+
+	versions := requestVersions(context.Request)
+	if len(versions) == 0 {
+		if res.optimisticLock.Required {
+			return nil, ErrVersionRequired
+		}
+		return nil, nil
+	}
+
+	var (
+		current = res.NewStruct()
+		qorCtx  = context.Context.Clone()
+		err     error
+	)
+
+	qorCtx.SetDB(context.GetDB().Clauses(clause.Locking{Strength: "UPDATE"}))
+	if res.Config.Singleton {
+		err = res.CallFindMany(current, qorCtx)
+	} else {
+		err = res.CallFindOne(current, nil, qorCtx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	version := res.RecordVersion(current)
+	for _, v := range versions {
+		if v == "*" || v == version {
+			return current, nil
+		}
+	}
+	return current, ErrVersionConflict
+}
+
+// bumpVersion increase integer version of the record based on its previous version, time versions like UpdatedAt are updated by gorm
+func (res *Resource) bumpVersion(record interface{}, previous interface{}) error {
+	field := res.versionField()
+	if field == nil {
+		return nil
+	}
+
+	var version int64
+	switch v := reflect.Indirect(reflect.ValueOf(previous)); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		version = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		version = int64(v.Uint())
+	default:
+		return nil
+	}
+	return field.Set(stdcontext.Background(), reflect.Indirect(reflect.ValueOf(record)), version+1)
+}
+
+// VersionDiff an attribute changed differently by current record and submitted values
+type VersionDiff struct {
+	Name    string
+	Label   string
+	Current interface{}
+	Yours   interface{}
+}
+
+// versionDiff compare attributes of edit form between current record and submitted values
+func (res *Resource) versionDiff(current, yours interface{}, context *Context) (diffs []VersionDiff) {
+	scope := utils.NewScope(res.Value)
+	for _, meta := range res.ConvertSectionToMetas(res.allowedSections(res.EditAttrs(), context, roles.Update)) {
+		if len(mergeFields(scope, meta.FieldName)) == 0 {
+			continue
+		}
+
+		if !reflect.DeepEqual(context.RawValueOf(current, meta), context.RawValueOf(yours, meta)) {
+			diffs = append(diffs, VersionDiff{
+				Name:    meta.Name,
+				Label:   meta.Label,
+				Current: context.FormattedValueOf(current, meta),
+				Yours:   context.FormattedValueOf(yours, meta),
+			})
+		}
+	}
+	return diffs
+}
+
+// versionConflict return differences of the stale update for templates
+func (context *Context) versionConflict() []VersionDiff {
+	if diffs, ok := context.Get(VersionConflictKey).([]VersionDiff); ok {
+		return diffs
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	for header, versions := range map[string][]string{
+		``:                nil,
+		`"3"`:             {"3"},
+		`W/"3", "4"`:      {"3", "4"},
+		`*`:               {"*"},
+		`"2023-01-02T03"`: {"2023-01-02T03"},
+	} {
+		if got := parseIfMatch(header); !reflect.DeepEqual(got, versions) {
+			t.Errorf("If-Match %v should be parsed as %v, got %v", header, versions, got)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	updatedAt := time.Date(2023, 1, 2, 3, 4, 5, 678901234, time.FixedZone("CST", 8*3600))
+	if version := versionString(updatedAt); version != "2023-01-01T19:04:05.678Z" {
+		t.Errorf("time version should be in UTC with milliseconds, got %v", version)
+	}
+
+	if version := versionString(&updatedAt); version != "2023-01-01T19:04:05.678Z" {
+		t.Errorf("time pointer version should be in UTC with milliseconds, got %v", version)
+	}
+
+	if version := versionString(uint(3)); version != "3" {
+		t.Errorf("integer version should be its value, got %v", version)
+	}
+}
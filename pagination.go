@@ -0,0 +1,381 @@
+package admin
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/simonedbarber/qor/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TotalCountMode how to count total records of a resource when paginating
+type TotalCountMode string
+
+const (
+	// TotalCountExact count records with `COUNT`, it is the default mode
+	TotalCountExact TotalCountMode = ""
+	// TotalCountEstimate estimate total records from database statistics if there are no conditions, fallback to `COUNT` if not possible
+	TotalCountEstimate TotalCountMode = "estimate"
+	// TotalCountSkip don't count records, only know if there is a next page
+	TotalCountSkip TotalCountMode = "skip"
+)
+
+// cursorColumn a column used to paginate with cursors
+type cursorColumn struct {
+	Field *schema.Field
+	Desc  bool
+}
+
+// pageCursor is encoded into opaque cursor, it holds sort values of the first/last record of a page
+type pageCursor struct {
+	Values []json.RawMessage
+	Before bool
+}
+
+// encodeCursor encode sort values to an opaque cursor, before means records before the values
+func encodeCursor(values []interface{}, before bool) string {
+	cursor := pageCursor{Before: before}
+	for _, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		cursor.Values = append(cursor.Values, raw)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decode opaque cursor to sort values for columns
+func decodeCursor(str string, columns []cursorColumn) (values []interface{}, before bool, err error) {
+	var (
+		cursor pageCursor
+		data   []byte
+	)
+
+	if data, err = base64.RawURLEncoding.DecodeString(str); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return
+	}
+
+	if len(cursor.Values) != len(columns) {
+		return nil, false, errors.New("cursor doesn't match current sorting")
+	}
+
+	for idx, column := range columns {
+		value := reflect.New(column.Field.FieldType)
+		if err = json.Unmarshal(cursor.Values[idx], value.Interface()); err != nil {
+			return nil, false, err
+		}
+		values = append(values, value.Elem().Interface())
+	}
+	return values, cursor.Before, nil
+}
+
+// cursorColumns return columns to paginate with cursors for order by param, primary keys will be appended to make sure the order is stable
+//
+// return false if resource doesn't use cursor pagination or sorting with relations, custom sort expressions, nullable columns
+func (res *Resource) cursorColumns(orderBy string) ([]cursorColumn, bool) {
+	if !res.Config.CursorPagination {
+		return nil, false
+	}
+
+	var (
+		columns []cursorColumn
+		scope   = utils.NewScope(res.Value)
+	)
+
+	for _, key := range ParseOrderBy(orderBy) {
+		attr, ok := res.sortAttrOf(key)
+		if !ok {
+			continue
+		}
+
+		if strings.Contains(attr, ".") {
+			return nil, false
+		}
+
+		meta := res.GetMeta(attr)
+		if meta == nil || meta.SortExpression != "" {
+			return nil, false
+		}
+
+		field := scope.LookUpField(meta.GetFieldName())
+		if field == nil || field.DBName == "" || nullableField(field) {
+			return nil, false
+		}
+		columns = append(columns, cursorColumn{Field: field, Desc: key.Desc})
+	}
+
+	if len(scope.PrimaryFields) == 0 {
+		return nil, false
+	}
+
+	for _, primaryField := range scope.PrimaryFields {
+		exists := false
+		for _, column := range columns {
+			if column.Field.DBName == primaryField.DBName {
+				exists = true
+			}
+		}
+
+		if !exists {
+			columns = append(columns, cursorColumn{Field: primaryField})
+		}
+	}
+	return columns, true
+}
+
+// nullableField return true if field's values could be NULL, e.g: pointers, sql.NullString without `not null` constraint,
+// keyset conditions like `column > ?` skip NULL rows, so they are paginated with LIMIT/OFFSET
+func nullableField(field *schema.Field) bool {
+	if field.PrimaryKey || field.NotNull {
+		return false
+	}
+
+	if field.FieldType.Kind() == reflect.Ptr {
+		return true
+	}
+
+	_, ok := reflect.New(field.FieldType).Interface().(sql.Scanner)
+	return ok
+}
+
+// paginateWithCursor sort db with cursor columns, and only query records after (or before) the cursor
+//
+//	sorting with `state, -id`, records after cursor (paid, 10):
+//	(orders.state > 'paid') OR (orders.state = 'paid' AND orders.id < 10)
+func (s *Searcher) paginateWithCursor(db *gorm.DB, columns []cursorColumn, cursor string) *gorm.DB {
+	var (
+		table          = utils.NewScope(s.Resource.Value).Table
+		values, before = []interface{}{}, false
+	)
+
+	if cursor != "" {
+		var err error
+		if values, before, err = decodeCursor(cursor, columns); err != nil {
+			// the cursor is outdated, e.g: sorting changed, show first page
+			values, before, cursor = nil, false, ""
+		}
+	}
+
+	s.Pagination.Cursor = cursor
+	s.cursorBefore = before
+
+	if len(values) > 0 {
+		var (
+			conditions []string
+			args       []interface{}
+		)
+
+		for idx, column := range columns {
+			var parts []string
+			for previous := range columns[:idx] {
+				parts = append(parts, fmt.Sprintf("%v.%v = ?", table, columns[previous].Field.DBName))
+				args = append(args, values[previous])
+			}
+
+			operator := ">"
+			if column.Desc != before {
+				operator = "<"
+			}
+			parts = append(parts, fmt.Sprintf("%v.%v %v ?", table, column.Field.DBName, operator))
+			args = append(args, values[idx])
+			conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+		}
+
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	for _, column := range columns {
+		direction := "ASC"
+		if column.Desc != before {
+			direction = "DESC"
+		}
+		db = db.Order(fmt.Sprintf("%v.%v %v", table, column.Field.DBName, direction))
+	}
+
+	return db
+}
+
+// estimateTotalCount estimate total count from database statistics, only works for PostgreSQL, MySQL without any conditions
+func (s *Searcher) estimateTotalCount(db *gorm.DB) (int64, bool) {
+	if s.Resource.Config.TotalCount != TotalCountEstimate {
+		return 0, false
+	}
+
+	if _, ok := db.Statement.Clauses["WHERE"]; ok || len(db.Statement.Joins) > 0 {
+		return 0, false
+	}
+
+	var (
+		total int64
+		table = utils.NewScope(s.Resource.Value).Table
+		sql   string
+	)
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		sql = "SELECT reltuples::bigint FROM pg_class WHERE relname = ?"
+	case "mysql":
+		sql = "SELECT table_rows FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	default:
+		return 0, false
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true}).Raw(sql, table).Scan(&total).Error; err != nil || total < 0 {
+		return 0, false
+	}
+	return total, true
+}
+
+// paginateResult trim extra record queried to detect next page, and generate cursors for cursor pagination
+func (s *Searcher) paginateResult(result interface{}) {
+	if s.probeLimit == 0 {
+		return
+	}
+
+	var (
+		results = reflect.Indirect(reflect.ValueOf(result))
+		hasMore bool
+	)
+
+	if results.Kind() != reflect.Slice {
+		return
+	}
+
+	if results.Len() > s.probeLimit {
+		hasMore = true
+		results.Set(results.Slice(0, s.probeLimit))
+	}
+
+	if s.cursorColumns == nil {
+		s.Pagination.Pages = s.Pagination.CurrentPage
+		if hasMore {
+			s.Pagination.Pages++
+		}
+		return
+	}
+
+	hasPrev, hasNext := s.Pagination.Cursor != "", hasMore
+	if s.cursorBefore {
+		// records before cursor are queried in reverse order
+		swap := reflect.Swapper(results.Interface())
+		for i, j := 0, results.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+		hasPrev, hasNext = hasMore, true
+	}
+
+	if results.Len() == 0 {
+		return
+	}
+
+	valuesOf := func(record reflect.Value) (values []interface{}) {
+		record = reflect.Indirect(record)
+		for _, column := range s.cursorColumns {
+			values = append(values, record.FieldByName(column.Field.Name).Interface())
+		}
+		return
+	}
+
+	if hasPrev {
+		s.Pagination.PrevCursor = encodeCursor(valuesOf(results.Index(0)), true)
+	}
+
+	if hasNext {
+		s.Pagination.NextCursor = encodeCursor(valuesOf(results.Index(results.Len()-1)), false)
+	}
+}
+
+// writePaginationHeaders write pagination information to response headers for JSON, XML requests, e.g:
+//
+//	X-Total-Count: 1000
+//	X-Next-Cursor: eyJWYWx1ZXMiOlsxMF0sIkJlZm9yZSI6ZmFsc2V9
+//	Link: </admin/orders?cursor=eyJWYWx1ZXMiOlsxMF0sIkJlZm9yZSI6ZmFsc2V9>; rel="next"
+func (context *Context) writePaginationHeaders() {
+	var (
+		pagination = context.Searcher.Pagination
+		header     = context.Writer.Header()
+		links      []string
+	)
+
+	if !pagination.TotalSkipped {
+		header.Set("X-Total-Count", fmt.Sprint(pagination.Total))
+		if pagination.TotalEstimated {
+			header.Set("X-Total-Count-Estimated", "true")
+		}
+	}
+
+	addLink := func(rel string, params ...interface{}) {
+		if link, err := context.patchCurrentURL(params...); err == nil {
+			links = append(links, fmt.Sprintf("<%v>; rel=%q", link, rel))
+		}
+	}
+
+	if pagination.UseCursor {
+		if pagination.NextCursor != "" {
+			header.Set("X-Next-Cursor", pagination.NextCursor)
+			addLink("next", "cursor", pagination.NextCursor)
+		}
+
+		if pagination.PrevCursor != "" {
+			header.Set("X-Prev-Cursor", pagination.PrevCursor)
+			addLink("prev", "cursor", pagination.PrevCursor)
+		}
+	} else if pagination.CurrentPage > 0 {
+		if pagination.CurrentPage < pagination.Pages {
+			addLink("next", "page", pagination.CurrentPage+1)
+		}
+
+		if pagination.CurrentPage > 1 {
+			addLink("prev", "page", pagination.CurrentPage-1)
+		}
+	}
+
+	if len(links) > 0 {
+		header.Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// paginationBody return pagination of index JSON response body for resources paginate with cursors or don't count total exactly,
+// their records are wrapped with the pagination, responses of other resources are still lists of records, e.g:
+//
+//	{"Records": [...], "Pagination": {"Total": 1000, "TotalEstimated": true, "PrevCursor": "", "NextCursor": "eyJWYWx1ZXMiOlsxMF0sIkJlZm9yZSI6ZmFsc2V9"}}
+func (context *Context) paginationBody() (map[string]interface{}, bool) {
+	if context.Resource == nil || context.Searcher == nil {
+		return nil, false
+	}
+
+	if config := context.Resource.Config; !config.CursorPagination && config.TotalCount == TotalCountExact {
+		return nil, false
+	}
+
+	pagination := context.Searcher.Pagination
+	body := map[string]interface{}{
+		"TotalEstimated": pagination.TotalEstimated,
+		"TotalSkipped":   pagination.TotalSkipped,
+		"PrevCursor":     pagination.PrevCursor,
+		"NextCursor":     pagination.NextCursor,
+	}
+
+	if !pagination.TotalSkipped {
+		body["Total"] = pagination.Total
+	}
+
+	if !pagination.UseCursor {
+		body["CurrentPage"] = pagination.CurrentPage
+		body["Pages"] = pagination.Pages
+	}
+	return body, true
+}
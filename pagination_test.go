@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	var (
+		now     = time.Now().Round(time.Second)
+		columns = []cursorColumn{
+			{Field: &schema.Field{Name: "State", FieldType: reflect.TypeOf("")}},
+			{Field: &schema.Field{Name: "CreatedAt", FieldType: reflect.TypeOf(time.Time{})}, Desc: true},
+			{Field: &schema.Field{Name: "ID", FieldType: reflect.TypeOf(uint(0))}},
+		}
+	)
+
+	cursor := encodeCursor([]interface{}{"paid", now, uint(10)}, true)
+	values, before, err := decodeCursor(cursor, columns)
+	if err != nil {
+		t.Fatalf("failed to decode cursor, got %v", err)
+	}
+
+	if !before {
+		t.Errorf("cursor should be decoded as before cursor")
+	}
+
+	if values[0] != "paid" || !values[1].(time.Time).Equal(now) || values[2] != uint(10) {
+		t.Errorf("cursor values should be decoded with columns' types, but got %#v", values)
+	}
+
+	if _, _, err := decodeCursor(cursor, columns[:2]); err == nil {
+		t.Errorf("cursor shouldn't be decoded if sorting columns changed")
+	}
+
+	if _, _, err := decodeCursor("invalid cursor", columns); err == nil {
+		t.Errorf("invalid cursor shouldn't be decoded")
+	}
+}
+
+func TestNullableField(t *testing.T) {
+	for _, c := range []struct {
+		Field    *schema.Field
+		Nullable bool
+	}{
+		{Field: &schema.Field{FieldType: reflect.TypeOf("")}},
+		{Field: &schema.Field{FieldType: reflect.TypeOf(time.Time{})}},
+		{Field: &schema.Field{FieldType: reflect.TypeOf(new(string))}, Nullable: true},
+		{Field: &schema.Field{FieldType: reflect.TypeOf(new(string)), NotNull: true}},
+		{Field: &schema.Field{FieldType: reflect.TypeOf(sql.NullString{})}, Nullable: true},
+		{Field: &schema.Field{FieldType: reflect.TypeOf(new(uint)), PrimaryKey: true}},
+	} {
+		if nullableField(c.Field) != c.Nullable {
+			t.Errorf("nullable of field with type %v should be %v", c.Field.FieldType, c.Nullable)
+		}
+	}
+}
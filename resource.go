@@ -27,6 +27,17 @@ type Config struct {
 	Singleton  bool
 	Invisible  bool
 	PageCount  int
+	// CursorPagination paginate with opaque cursors (keyset pagination) instead of LIMIT/OFFSET, it is much faster for large tables, sorting with nullable columns falls back to LIMIT/OFFSET
+	// records of JSON index responses are wrapped with pagination like `{"Records": [...], "Pagination": {...}}` if CursorPagination or TotalCount isn't TotalCountExact
+	CursorPagination bool
+	// TotalCount how to count total records, could be TotalCountExact (default), TotalCountEstimate, TotalCountSkip
+	TotalCount TotalCountMode
+	// QueryLanguage parse search box's keyword as search query, e.g: `status:paid total>100 "free text"`, refer SearchQuery for details
+	QueryLanguage bool
+	// BulkDelete add a batch action to delete selected records, dependent records are checked like deleting a record
+	BulkDelete bool
+	// Badge return badge of resource's menu like count of records waiting for review, refer Resource.ScopeCountBadge
+	Badge func(context *Context) string
 }
 
 // Resource is the most important thing for qor admin, every model is defined as a resource, qor admin will genetate management interface based on its definition
@@ -36,15 +47,26 @@ type Resource struct {
 	ParentResource *Resource
 	ChildResources []*Resource
 	SearchHandler  func(keyword string, context *qor.Context) *gorm.DB
-
-	params   string
-	admin    *Admin
-	metas    []*Meta
-	actions  []*Action
-	scopes   []*Scope
-	filters  []*Filter
-	mounted  bool
-	sections struct {
+	// DuplicateHandler adjust copy of a record made by built-in action Duplicate, e.g: append " (copy)" to name
+	DuplicateHandler func(original, duplicate interface{}, context *Context) error
+
+	searchAttrs     []string
+	searchBackend   SearchBackend
+	trash           *TrashConfig
+	deleteBehaviors map[string]DeleteBehavior
+	merge           *MergeConfig
+	bulkEdit        *BulkEditConfig
+	optimisticLock  *OptimisticLockConfig
+	editLock        *EditLockConfig
+	hooks           map[string][]HookHandler
+	params          string
+	admin           *Admin
+	metas           []*Meta
+	actions         []*Action
+	scopes          []*Scope
+	filters         []*Filter
+	mounted         bool
+	sections        struct {
 		IndexSections                  []*Section
 		OverriddingIndexAttrs          bool
 		OverriddingIndexAttrsCallbacks []func()
@@ -669,16 +691,19 @@ func (res *Resource) OverrideShowAttrs(fc func()) {
 	}
 }
 
-// SortableAttrs set sortable attributes, sortable attributes are clickable to sort data in index page
+// SortableAttrs set sortable attributes, sortable attributes are clickable to sort data in index page, e.g:
+//
+//	order.Meta(&admin.Meta{Name: "Total", SortExpression: "(price * quantity)"})
+//	order.SortableAttrs("State", "Total", "Customer.Name", "CreatedAt")
+//	// Sort orders with its state, a custom expression, its customer's name (belongs_to relation) or created time
 func (res *Resource) SortableAttrs(columns ...string) []string {
 	if len(columns) != 0 || res.sections.SortableAttrs == nil {
 		if len(columns) == 0 {
 			columns = res.ConvertSectionToStrings(res.sections.IndexSections)
 		}
 		res.sections.SortableAttrs = &[]string{}
-		scope := utils.NewScope(res.Value)
 		for _, column := range columns {
-			if field := scope.LookUpField(column); field != nil && field.DBName != "" {
+			if res.sortableColumn(column) {
 				attrs := append(*res.sections.SortableAttrs, column)
 				res.sections.SortableAttrs = &attrs
 			}
@@ -698,7 +723,12 @@ func (res *Resource) SearchAttrs(columns ...string) []string {
 		}
 
 		if len(columns) > 0 {
+			res.searchAttrs = columns
 			res.SearchHandler = func(keyword string, context *qor.Context) *gorm.DB {
+				if res.searchBackend != nil {
+					return res.searchWithBackend(keyword, context)
+				}
+
 				var filterFields []filterField
 				for _, column := range columns {
 					filterFields = append(filterFields, filterField{FieldName: column})
@@ -754,6 +784,10 @@ func (res *Resource) Meta(meta *Meta) *Meta {
 		if meta.Collection != nil {
 			oldMeta.Collection = meta.Collection
 		}
+
+		if meta.SortExpression != "" {
+			oldMeta.SortExpression = meta.SortExpression
+		}
 		meta = oldMeta
 	} else {
 		res.metas = append(res.metas, meta)
@@ -165,6 +165,9 @@ func (admin *Admin) NewServeMux(prefix string) http.Handler {
 	adminController := &Controller{Admin: admin}
 	router.Get("", adminController.Dashboard)
 	router.Get("/!search", adminController.SearchCenter)
+	router.Get("/!command_palette", adminController.CommandPalette)
+	router.Get("/!favourites", adminController.Favourites)
+	router.Post("/!favourites", adminController.Favourites)
 
 	browserUserAgentRegexp := regexp.MustCompile("Mozilla|Gecko|WebKit|MSIE|Opera")
 	router.Use(&Middleware{
@@ -332,6 +335,15 @@ func (admin *Admin) RegisterResourceRouters(res *Resource, actions ...string) {
 				// Index
 				res.RegisterRoute("GET", "/", adminController.Index, &RouteConfig{PermissionMode: roles.Read})
 
+				// Index Settings
+				for _, method := range []string{"GET", "POST", "PUT", "DELETE"} {
+					res.RegisterRoute(method, "/!index_settings", adminController.IndexSettings, &RouteConfig{PermissionMode: roles.Read})
+					res.RegisterRoute(method, "/!saved_filters", adminController.SavedFilters, &RouteConfig{PermissionMode: roles.Read})
+				}
+
+				// Live Events
+				res.RegisterRoute("GET", "/!events", adminController.LiveEvents, &RouteConfig{PermissionMode: roles.Read})
+
 				// Show
 				res.RegisterRoute("GET", primaryKeyParams, adminController.Show, &RouteConfig{PermissionMode: roles.Read})
 			}
@@ -339,6 +351,9 @@ func (admin *Admin) RegisterResourceRouters(res *Resource, actions ...string) {
 			if !res.Config.Singleton {
 				// Delete
 				res.RegisterRoute("DELETE", primaryKeyParams, adminController.Delete, &RouteConfig{PermissionMode: roles.Delete})
+				// Delete Preview
+				res.RegisterRoute("GET", "/!delete_preview", adminController.DeletePreview, &RouteConfig{PermissionMode: roles.Delete})
+				res.RegisterRoute("GET", path.Join(primaryKeyParams, "!delete_preview"), adminController.DeletePreview, &RouteConfig{PermissionMode: roles.Delete})
 			}
 		}
 	}
@@ -0,0 +1,519 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+)
+
+// SavedFiltersKey settings key of legacy saved filters, which were saved without owners and shared by all users, they are listed as global filters
+const SavedFiltersKey = "saved_filters"
+
+// PrivateSavedFiltersKey settings key used to save user's private saved filters
+const PrivateSavedFiltersKey = "private_saved_filters"
+
+// SharedSavedFiltersKey settings key used to save saved filters shared with roles or everyone
+const SharedSavedFiltersKey = "shared_saved_filters"
+
+// SavedFilterVisibility who could use a saved filter
+type SavedFilterVisibility string
+
+const (
+	// SavedFilterPrivate saved filter could only be used by its owner
+	SavedFilterPrivate SavedFilterVisibility = "private"
+	// SavedFilterRole saved filter could be used by users have one of its roles
+	SavedFilterRole SavedFilterVisibility = "role"
+	// SavedFilterGlobal saved filter could be used by everyone
+	SavedFilterGlobal SavedFilterVisibility = "global"
+)
+
+// SavedFilter saved filter settings
+type SavedFilter struct {
+	Name string
+	URL  string
+	// Visibility who could use the saved filter, default is SavedFilterPrivate
+	Visibility SavedFilterVisibility `json:",omitempty"`
+	// Roles roles could use the saved filter if its visibility is SavedFilterRole
+	Roles []string `json:",omitempty"`
+	// Owner, OwnerName ID and name of the user created the saved filter
+	Owner     string `json:",omitempty"`
+	OwnerName string `json:",omitempty"`
+	// Default open index page with the saved filter if there is no params
+	Default bool `json:",omitempty"`
+	// Columns, OrderBy column and sorting preset applied with the saved filter
+	Columns []string `json:",omitempty"`
+	OrderBy string   `json:",omitempty"`
+	// Invalid params dropped from URL when loading as they don't match resource's current filters, scopes or columns anymore
+	Invalid []string `json:",omitempty"`
+}
+
+// IsShared the saved filter is visible to other users
+func (filter SavedFilter) IsShared() bool {
+	return filter.Visibility == SavedFilterRole || filter.Visibility == SavedFilterGlobal
+}
+
+// visibleTo check if the saved filter could be used by the user with roles
+func (filter SavedFilter) visibleTo(userID string, userRoles []string) bool {
+	switch filter.Visibility {
+	case SavedFilterGlobal:
+		return true
+	case SavedFilterRole:
+		if filter.Owner == userID {
+			return true
+		}
+		for _, role := range filter.Roles {
+			for _, userRole := range userRoles {
+				if role == userRole {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return filter.Owner == userID
+	}
+}
+
+// savedFilterUserID return ID of current user that used as owner of saved filters
+func (context *Context) savedFilterUserID() string {
+	if context.CurrentUser != nil {
+		return fmt.Sprint(context.CurrentUser.GetID())
+	}
+	return ""
+}
+
+// privateSavedFilters return current user's private saved filters
+func (context *Context) privateSavedFilters() (filters []SavedFilter, err error) {
+	err = context.Admin.SettingsStorage.Get(PrivateSavedFiltersKey, &filters, context)
+	for idx := range filters {
+		filters[idx].Visibility, filters[idx].Roles, filters[idx].Owner = SavedFilterPrivate, nil, context.savedFilterUserID()
+	}
+	return filters, err
+}
+
+func (context *Context) sharedSavedFilters() (filters []SavedFilter, err error) {
+	err = context.Admin.SettingsStorage.Get(SharedSavedFiltersKey, &filters, context.savedFiltersContext())
+	return filters, err
+}
+
+// legacySavedFilters return saved filters saved before saved filters have owners, they are shared by all users as they used to be, so they are global filters without owner
+func (context *Context) legacySavedFilters() (filters []SavedFilter, err error) {
+	err = context.Admin.SettingsStorage.Get(SavedFiltersKey, &filters, context.savedFiltersContext())
+	for idx := range filters {
+		filters[idx].Visibility, filters[idx].Roles, filters[idx].Owner = SavedFilterGlobal, nil, ""
+	}
+	return filters, err
+}
+
+// savedFiltersContext return context without current user, which loads settings shared by all users
+func (context *Context) savedFiltersContext() *Context {
+	ctx := context.clone()
+	ctx.Context = context.Context.Clone()
+	ctx.CurrentUser = nil
+	return ctx
+}
+
+// savedFilters return saved filters of current resource could be used by current user, user's private filters go first, URLs are validated with resource's current filters
+func (context *Context) savedFilters() (filters []SavedFilter) {
+	if context.Resource == nil || context.Admin.SettingsStorage == nil {
+		return nil
+	}
+
+	var (
+		userID   = context.savedFilterUserID()
+		names    = map[string]bool{}
+		private  []SavedFilter
+		shared   []SavedFilter
+		legacy   []SavedFilter
+		err      error
+		validate = func(filter SavedFilter) {
+			if !names[filter.Name] && filter.visibleTo(userID, context.Roles) {
+				names[filter.Name] = true
+				filters = append(filters, context.Resource.validateSavedFilter(filter, context))
+			}
+		}
+	)
+
+	if private, err = context.privateSavedFilters(); err == nil {
+		for _, filter := range private {
+			validate(filter)
+		}
+	}
+
+	if shared, err = context.sharedSavedFilters(); err == nil {
+		for _, filter := range shared {
+			validate(filter)
+		}
+	}
+
+	if legacy, err = context.legacySavedFilters(); err == nil {
+		for _, filter := range legacy {
+			validate(filter)
+		}
+	}
+	return filters
+}
+
+// findSavedFilter find a saved filter could be used by current user with name
+func (context *Context) findSavedFilter(name string) (SavedFilter, bool) {
+	for _, filter := range context.savedFilters() {
+		if filter.Name == name {
+			return filter, true
+		}
+	}
+	return SavedFilter{}, false
+}
+
+// defaultSavedFilter return saved filter should be opened by default, user's private default filter is preferred
+func (context *Context) defaultSavedFilter() (SavedFilter, bool) {
+	for _, filter := range context.savedFilters() {
+		if filter.Default && len(filter.Invalid) == 0 {
+			return filter, true
+		}
+	}
+	return SavedFilter{}, false
+}
+
+// hasSharedSavedFilterPermission check if current user could save saved filters shared with other users
+func (context *Context) hasSharedSavedFilterPermission() bool {
+	if context.Admin.SharedSavedFilterPermission == nil {
+		return true
+	}
+
+	var userRoles []interface{}
+	for _, role := range context.Roles {
+		userRoles = append(userRoles, role)
+	}
+	return context.Admin.SharedSavedFilterPermission.HasPermission(roles.Create, userRoles...)
+}
+
+// savedFiltersMutex serialize changes of saved filters in current process, settings storages implement settingsLocker to serialize them across processes
+var savedFiltersMutex sync.Mutex
+
+// settingsLocker lock settings shared by all users in context's transaction until it is committed
+type settingsLocker interface {
+	lock(key string, res *Resource, context *Context) error
+}
+
+// changeSavedFilters change saved filters in a transaction, shared and legacy filters are locked, so concurrent changes don't overwrite each other
+func (context *Context) changeSavedFilters(change func(ctx *Context) error) error {
+	savedFiltersMutex.Lock()
+	defer savedFiltersMutex.Unlock()
+
+	return context.GetDB().Transaction(func(tx *gorm.DB) error {
+		ctx := context.clone()
+		ctx.Context = context.Context.Clone()
+		ctx.SetDB(tx)
+
+		if locker, ok := context.Admin.SettingsStorage.(settingsLocker); ok {
+			for _, key := range []string{SharedSavedFiltersKey, SavedFiltersKey} {
+				if err := locker.lock(key, context.Resource, ctx); err != nil {
+					return err
+				}
+			}
+		}
+		return change(ctx)
+	})
+}
+
+// saveSavedFilter create or update a saved filter of current resource, shared filters could only be updated by their owners, saving a shared filter with name of a legacy filter replaces the legacy filter
+func (context *Context) saveSavedFilter(filter SavedFilter) error {
+	filter.Name = strings.TrimSpace(filter.Name)
+	if filter.Name == "" {
+		return errors.New("saved filter's name can't be blank")
+	}
+
+	if (filter.Visibility == SavedFilterRole || filter.Visibility == SavedFilterGlobal) && !context.hasSharedSavedFilterPermission() {
+		return roles.ErrPermissionDenied
+	}
+
+	switch filter.Visibility {
+	case SavedFilterRole:
+		// filters could only be shared with roles of current user
+		var sharedRoles []string
+		for _, role := range filter.Roles {
+			for _, userRole := range context.Roles {
+				if role == userRole {
+					sharedRoles = append(sharedRoles, role)
+					break
+				}
+			}
+		}
+
+		if filter.Roles = sharedRoles; len(filter.Roles) == 0 {
+			filter.Roles = context.Roles
+		}
+	case SavedFilterGlobal:
+		filter.Roles = nil
+	default:
+		filter.Visibility, filter.Roles = SavedFilterPrivate, nil
+	}
+
+	filter = context.Resource.validateSavedFilter(filter, context)
+	filter.Owner, filter.Invalid = context.savedFilterUserID(), nil
+	if context.CurrentUser != nil {
+		filter.OwnerName = context.CurrentUser.DisplayName()
+	}
+
+	return context.changeSavedFilters(func(ctx *Context) error {
+		private, err := ctx.privateSavedFilters()
+		if err != nil {
+			return err
+		}
+
+		shared, err := ctx.sharedSavedFilters()
+		if err != nil {
+			return err
+		}
+
+		for _, f := range shared {
+			if f.Name == filter.Name && f.Owner != filter.Owner {
+				return fmt.Errorf("saved filter %v has been shared by %v", filter.Name, f.OwnerName)
+			}
+		}
+
+		var newPrivate, newShared []SavedFilter
+		if filter.IsShared() {
+			newShared = append(newShared, filter)
+		} else {
+			newPrivate = append(newPrivate, filter)
+		}
+
+		for _, f := range private {
+			if f.Name != filter.Name {
+				// only one private default filter for a user
+				f.Default = f.Default && !(filter.Default && !filter.IsShared())
+				newPrivate = append(newPrivate, f)
+			}
+		}
+
+		for _, f := range shared {
+			if f.Name != filter.Name {
+				// only one shared default filter for an owner
+				f.Default = f.Default && !(filter.Default && filter.IsShared() && f.Owner == filter.Owner)
+				newShared = append(newShared, f)
+			}
+		}
+
+		if err := ctx.Admin.SettingsStorage.Save(PrivateSavedFiltersKey, newPrivate, ctx.Resource, ctx.CurrentUser, ctx); err != nil {
+			return err
+		}
+
+		if filter.IsShared() || len(newShared) != len(shared) {
+			if err := ctx.Admin.SettingsStorage.Save(SharedSavedFiltersKey, newShared, ctx.Resource, nil, ctx); err != nil {
+				return err
+			}
+		}
+
+		if filter.IsShared() {
+			return ctx.deleteLegacySavedFilter(filter.Name)
+		}
+		return nil
+	})
+}
+
+// deleteSavedFilter delete current user's private saved filter, shared saved filter owned by current user, or legacy saved filter if current user could share saved filters
+func (context *Context) deleteSavedFilter(name string) error {
+	return context.changeSavedFilters(func(ctx *Context) error {
+		private, err := ctx.privateSavedFilters()
+		if err != nil {
+			return err
+		}
+
+		var newPrivate []SavedFilter
+		for _, filter := range private {
+			if filter.Name != name {
+				newPrivate = append(newPrivate, filter)
+			}
+		}
+
+		if len(newPrivate) != len(private) {
+			return ctx.Admin.SettingsStorage.Save(PrivateSavedFiltersKey, newPrivate, ctx.Resource, ctx.CurrentUser, ctx)
+		}
+
+		shared, err := ctx.sharedSavedFilters()
+		if err != nil {
+			return err
+		}
+
+		var newShared []SavedFilter
+		for _, filter := range shared {
+			if filter.Name != name {
+				newShared = append(newShared, filter)
+			} else if filter.Owner != ctx.savedFilterUserID() {
+				return fmt.Errorf("saved filter %v could only be deleted by %v", name, filter.OwnerName)
+			}
+		}
+
+		if len(newShared) != len(shared) {
+			return ctx.Admin.SettingsStorage.Save(SharedSavedFiltersKey, newShared, ctx.Resource, nil, ctx)
+		}
+
+		legacy, err := ctx.legacySavedFilters()
+		if err != nil {
+			return err
+		}
+
+		for _, filter := range legacy {
+			if filter.Name == name && !ctx.hasSharedSavedFilterPermission() {
+				return roles.ErrPermissionDenied
+			}
+		}
+		return ctx.deleteLegacySavedFilter(name)
+	})
+}
+
+// deleteLegacySavedFilter delete legacy saved filter with the name, other legacy filters are kept as they were saved
+func (context *Context) deleteLegacySavedFilter(name string) error {
+	legacy, err := context.legacySavedFilters()
+	if err != nil {
+		return err
+	}
+
+	var newLegacy []SavedFilter
+	for _, filter := range legacy {
+		if filter.Name != name {
+			filter.Visibility = ""
+			newLegacy = append(newLegacy, filter)
+		}
+	}
+
+	if len(newLegacy) == len(legacy) {
+		return nil
+	}
+	return context.Admin.SettingsStorage.Save(SavedFiltersKey, newLegacy, context.Resource, nil, context)
+}
+
+// savedFilterFromRequest parse saved filter from request form, e.g:
+//
+//	name=Paid&url=/admin/orders?scopes=Paid&visibility=role&roles[]=manager&default=true&columns[]=Code&columns[]=Total&order_by=-total
+func savedFilterFromRequest(context *Context) SavedFilter {
+	form := context.Request.Form
+	filter := SavedFilter{
+		Name:       form.Get("name"),
+		URL:        form.Get("url"),
+		Visibility: SavedFilterVisibility(form.Get("visibility")),
+		Roles:      form["roles[]"],
+		OrderBy:    form.Get("order_by"),
+	}
+
+	filter.Default = form.Get("default") == "true" || form.Get("default") == "1"
+	for _, key := range []string{"columns[]", "columns"} {
+		for _, value := range form[key] {
+			for _, column := range strings.Split(value, ",") {
+				if column = strings.TrimSpace(column); column != "" {
+					filter.Columns = append(filter.Columns, column)
+				}
+			}
+		}
+	}
+	return filter
+}
+
+// validateSavedFilter check saved filter's URL with resource's current filters, scopes, sorting and columns, invalid params are dropped and recorded in Invalid, presets are applied to URL
+func (res *Resource) validateSavedFilter(filter SavedFilter, context *Context) SavedFilter {
+	var (
+		query   = url.Values{}
+		invalid = map[string]bool{}
+	)
+
+	if u, err := url.Parse(filter.URL); err == nil {
+		query = u.Query()
+	}
+
+	for key, values := range query {
+		valid := true
+		switch {
+		case filterRegexp.MatchString(key):
+			valid = false
+			name := filterRegexp.FindStringSubmatch(key)[1]
+			for _, f := range res.filters {
+				if f.Name == name && (f.Visible == nil || f.Visible(context)) {
+					valid = true
+				}
+			}
+		case key == "scopes":
+			for _, value := range values {
+				found := false
+				for _, scope := range res.scopes {
+					found = found || scope.Name == value
+				}
+				valid = valid && found
+			}
+		case key == "order_by":
+			valid = res.isSortableOrder(query.Get(key))
+		case key == FilterGroupParam:
+			group, err := ParseFilterGroup(query.Get(key))
+			valid = err == nil && group.Validate(res) == nil
+		case key == "columns":
+			for _, value := range values {
+				for _, column := range strings.Split(value, ",") {
+					valid = valid && isContainsColumn(metaNames(res.availableIndexMetas(context)), column)
+				}
+			}
+		case key == "page" || key == "cursor" || key == "delete_saved_filter" || strings.HasPrefix(key, "filter_saving_"):
+			query.Del(key)
+			continue
+		}
+
+		if !valid {
+			invalid[key] = true
+			query.Del(key)
+		}
+	}
+
+	if filter.OrderBy != "" {
+		if res.isSortableOrder(filter.OrderBy) {
+			query.Set("order_by", filter.OrderBy)
+		} else {
+			invalid["order_by"] = true
+			filter.OrderBy = ""
+		}
+	}
+
+	if len(filter.Columns) > 0 {
+		var (
+			columns   []string
+			available = metaNames(res.availableIndexMetas(context))
+		)
+
+		for _, column := range filter.Columns {
+			if isContainsColumn(available, column) {
+				columns = append(columns, column)
+			} else {
+				invalid["columns"] = true
+			}
+		}
+
+		if filter.Columns = columns; len(columns) > 0 {
+			query.Set("columns", strings.Join(columns, ","))
+		}
+	}
+
+	filter.Invalid = nil
+	for key := range invalid {
+		filter.Invalid = append(filter.Invalid, key)
+	}
+	sort.Strings(filter.Invalid)
+
+	// saved filters could only link to the resource's index page
+	filter.URL = context.URLFor(res)
+	if len(query) > 0 {
+		filter.URL += "?" + query.Encode()
+	}
+	return filter
+}
+
+// metaNames return names of metas
+func metaNames(metas []*Meta) (names []string) {
+	for _, meta := range metas {
+		names = append(names, meta.Name)
+	}
+	return
+}
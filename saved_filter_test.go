@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/simonedbarber/qor"
+)
+
+func TestSavedFilterVisibleTo(t *testing.T) {
+	for _, testCase := range []struct {
+		Filter  SavedFilter
+		UserID  string
+		Roles   []string
+		Visible bool
+	}{
+		{Filter: SavedFilter{Owner: "1"}, UserID: "1", Visible: true},
+		{Filter: SavedFilter{Owner: "1"}, UserID: "2", Visible: false},
+		{Filter: SavedFilter{}, UserID: "2", Visible: false},
+		{Filter: SavedFilter{Owner: "1", Visibility: SavedFilterGlobal}, UserID: "2", Visible: true},
+		{Filter: SavedFilter{Owner: "1", Visibility: SavedFilterRole, Roles: []string{"manager"}}, UserID: "2", Roles: []string{"editor", "manager"}, Visible: true},
+		{Filter: SavedFilter{Owner: "1", Visibility: SavedFilterRole, Roles: []string{"manager"}}, UserID: "2", Roles: []string{"editor"}, Visible: false},
+		{Filter: SavedFilter{Owner: "1", Visibility: SavedFilterRole, Roles: []string{"manager"}}, UserID: "1", Visible: true},
+	} {
+		if visible := testCase.Filter.visibleTo(testCase.UserID, testCase.Roles); visible != testCase.Visible {
+			t.Errorf("saved filter %#v visible to user %v with roles %v should be %v", testCase.Filter, testCase.UserID, testCase.Roles, testCase.Visible)
+		}
+	}
+}
+
+func TestSavedFilterFromRequest(t *testing.T) {
+	form := url.Values{
+		"name":       {"Paid"},
+		"url":        {"/admin/orders?scopes=Paid"},
+		"visibility": {"role"},
+		"roles[]":    {"manager"},
+		"default":    {"true"},
+		"columns[]":  {"Code,Total", "State"},
+		"order_by":   {"-total"},
+	}
+
+	filter := savedFilterFromRequest(&Context{Context: &qor.Context{Request: &http.Request{Form: form}}})
+	expected := SavedFilter{
+		Name:       "Paid",
+		URL:        "/admin/orders?scopes=Paid",
+		Visibility: SavedFilterRole,
+		Roles:      []string{"manager"},
+		Default:    true,
+		Columns:    []string{"Code", "Total", "State"},
+		OrderBy:    "-total",
+	}
+
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("saved filter should be %#v, but got %#v", expected, filter)
+	}
+}
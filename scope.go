@@ -21,4 +21,6 @@ type Scope struct {
 	Visible func(context *Context) bool
 	Handler func(*gorm.DB, *qor.Context) *gorm.DB
 	Default bool
+	// ShowCount show count of records next to the scope, scopes in same group are counted with one query
+	ShowCount bool
 }
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SearchBackend full-text search backend, resource's SearchHandler delegates to it after calling res.UseSearchBackend, refer SQLiteFTS5SearchBackend, PostgresSearchBackend
+type SearchBackend interface {
+	// Migrate create search index for the resource
+	Migrate(res *Resource, db *gorm.DB) error
+	// Search filter context's db with keyword, results should be ordered by relevance
+	Search(keyword string, res *Resource, context *qor.Context) *gorm.DB
+	// Index create or update search document of the record
+	Index(record interface{}, res *Resource, context *qor.Context) error
+	// Unindex remove search document of the record
+	Unindex(record interface{}, res *Resource, context *qor.Context) error
+}
+
+// UseSearchBackend search resource with full-text search backend, the search index is migrated with admin's DB, and maintained when saving, deleting records with the resource, e.g:
+//
+//	product.SearchAttrs("Name", "Code", "Category.Name")
+//	product.UseSearchBackend(&admin.PostgresSearchBackend{Language: "english"})
+//
+// searchable attributes are used to build search documents, relations like `Category.Name` are indexed if they are loaded when saving, as SaveHandler, DeleteHandler are wrapped to maintain the index, customize them before using search backend
+func (res *Resource) UseSearchBackend(backend SearchBackend) {
+	res.searchBackend = backend
+
+	if db := res.admin.DB; db != nil {
+		if err := backend.Migrate(res, db); err != nil {
+			utils.ExitWithMsg("Failed to migrate search index for resource %v: %v", res.Name, err)
+		}
+	}
+
+	res.SearchHandler = res.searchWithBackend
+
+	// the record and its search document are saved in handler's transaction, which is nested in caller's transaction if there is
+	saveHandler := res.SaveHandler
+	res.SaveHandler = func(value interface{}, context *qor.Context) error {
+		return context.GetDB().Transaction(func(tx *gorm.DB) error {
+			ctx := context.Clone()
+			ctx.SetDB(tx)
+			if err := saveHandler(value, ctx); err != nil {
+				return err
+			}
+			return backend.Index(value, res, ctx)
+		})
+	}
+
+	deleteHandler := res.DeleteHandler
+	res.DeleteHandler = func(value interface{}, context *qor.Context) error {
+		return context.GetDB().Transaction(func(tx *gorm.DB) error {
+			ctx := context.Clone()
+			ctx.SetDB(tx)
+			if err := deleteHandler(value, ctx); err != nil {
+				return err
+			}
+			return backend.Unindex(value, res, ctx)
+		})
+	}
+}
+
+// searchWithBackend search with resource's search backend
+func (res *Resource) searchWithBackend(keyword string, context *qor.Context) *gorm.DB {
+	if strings.TrimSpace(keyword) == "" {
+		return context.GetDB()
+	}
+	return res.searchBackend.Search(keyword, res, context)
+}
+
+// RebuildSearchIndex index all records of the resource with its search backend, used to index existing records
+func (res *Resource) RebuildSearchIndex(context *qor.Context) error {
+	if res.searchBackend == nil {
+		return fmt.Errorf("resource %v doesn't use search backend", res.Name)
+	}
+
+	results := res.NewSlice()
+	return context.GetDB().Model(res.Value).FindInBatches(results, 500, func(tx *gorm.DB, batch int) error {
+		records := reflect.Indirect(reflect.ValueOf(results))
+		for i := 0; i < records.Len(); i++ {
+			record := records.Index(i)
+			if record.Kind() != reflect.Ptr {
+				record = record.Addr()
+			}
+
+			if err := res.searchBackend.Index(record.Interface(), res, context); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// searchColumns return attributes used to build search documents
+func (res *Resource) searchColumns() []string {
+	if len(res.searchAttrs) > 0 {
+		return res.searchAttrs
+	}
+	return res.ConvertSectionToStrings(res.sections.IndexSections)
+}
+
+// searchPrimaryField return primary field of resource, search backends only support resources with one primary field
+func (res *Resource) searchPrimaryField() (*schema.Schema, *schema.Field, error) {
+	scope := utils.NewScope(res.Value)
+	if len(scope.PrimaryFields) != 1 {
+		return scope, nil, fmt.Errorf("search backend requires one primary field for resource %v", res.Name)
+	}
+	return scope, scope.PrimaryFields[0], nil
+}
+
+// searchRecordID return primary value of record
+func (res *Resource) searchRecordID(record interface{}) (interface{}, error) {
+	_, field, err := res.searchPrimaryField()
+	if err != nil {
+		return nil, err
+	}
+
+	value, zero := field.ValueOf(context.Background(), reflect.Indirect(reflect.ValueOf(record)))
+	if zero {
+		return nil, fmt.Errorf("primary value of %v is blank", res.Name)
+	}
+	return value, nil
+}
+
+// searchDocument build search document of record with searchable attributes, attributes could be paths of relations, e.g: Category.Name
+func searchDocument(record interface{}, columns []string) string {
+	var (
+		texts   []string
+		collect func(value reflect.Value, names []string)
+	)
+
+	collect = func(value reflect.Value, names []string) {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return
+			}
+			value = value.Elem()
+		}
+
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < value.Len(); i++ {
+				collect(value.Index(i), names)
+			}
+			return
+		}
+
+		if len(names) > 0 {
+			if value.Kind() == reflect.Struct {
+				if field := value.FieldByName(names[0]); field.IsValid() {
+					collect(field, names[1:])
+				}
+			}
+			return
+		}
+
+		if !value.CanInterface() {
+			return
+		}
+
+		var text string
+		switch v := value.Interface().(type) {
+		case time.Time:
+			if !v.IsZero() {
+				text = v.Format("2006-01-02")
+			}
+		case []byte:
+			text = string(v)
+		default:
+			if value.Kind() == reflect.Struct {
+				ptr := reflect.New(value.Type())
+				ptr.Elem().Set(value)
+				text = utils.Stringify(ptr.Interface())
+			} else if !value.IsZero() {
+				text = fmt.Sprint(v)
+			}
+		}
+
+		if text = strings.TrimSpace(text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	for _, column := range columns {
+		collect(reflect.ValueOf(record), strings.Split(column, "."))
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/simonedbarber/qor"
+	"gorm.io/gorm"
+)
+
+// postgresSearchConfigRegexp valid text search configuration name
+var postgresSearchConfigRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// PostgresSearchBackend full-text search backend with Postgres's tsvector, search documents are saved in column `search_vector` with a GIN index, keyword is parsed with websearch_to_tsquery, results are ranked with ts_rank
+type PostgresSearchBackend struct {
+	// Language text search configuration, default is `simple`
+	Language string
+	// Column tsvector column name, default is `search_vector`
+	Column string
+}
+
+func (backend *PostgresSearchBackend) language() string {
+	if postgresSearchConfigRegexp.MatchString(backend.Language) {
+		return backend.Language
+	}
+	return "simple"
+}
+
+func (backend *PostgresSearchBackend) column() string {
+	if backend.Column != "" {
+		return backend.Column
+	}
+	return "search_vector"
+}
+
+// Migrate add tsvector column and its GIN index to resource's table
+func (backend *PostgresSearchBackend) Migrate(res *Resource, db *gorm.DB) error {
+	scope, _, err := res.searchPrimaryField()
+	if err != nil {
+		return err
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE %v ADD COLUMN IF NOT EXISTS %v tsvector", scope.Table, backend.column())).Error; err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %v_%v_idx ON %v USING GIN (%v)", scope.Table, backend.column(), scope.Table, backend.column())).Error
+}
+
+// Search filter records match keyword, ordered by ts_rank
+func (backend *PostgresSearchBackend) Search(keyword string, res *Resource, context *qor.Context) *gorm.DB {
+	db := context.GetDB()
+	scope, field, err := res.searchPrimaryField()
+	if err != nil {
+		context.AddError(err)
+		return db
+	}
+
+	return db.Joins(
+		fmt.Sprintf(
+			"JOIN (SELECT search_records.%v AS record_id, ts_rank(search_records.%v, search_query) AS search_rank FROM %v search_records, websearch_to_tsquery('%v', ?) search_query WHERE search_records.%v @@ search_query) AS search_results ON search_results.record_id = %v.%v",
+			field.DBName, backend.column(), scope.Table, backend.language(), backend.column(), scope.Table, field.DBName,
+		),
+		keyword,
+	).Order("search_results.search_rank DESC")
+}
+
+// Index update tsvector of the record
+func (backend *PostgresSearchBackend) Index(record interface{}, res *Resource, context *qor.Context) error {
+	scope, field, err := res.searchPrimaryField()
+	if err != nil {
+		return err
+	}
+
+	recordID, err := res.searchRecordID(record)
+	if err != nil {
+		return err
+	}
+
+	return context.GetDB().Exec(
+		fmt.Sprintf("UPDATE %v SET %v = to_tsvector('%v', ?) WHERE %v = ?", scope.Table, backend.column(), backend.language(), field.DBName),
+		searchDocument(record, res.searchColumns()), recordID,
+	).Error
+}
+
+// Unindex clear tsvector of the record, it is required for soft deleted records
+func (backend *PostgresSearchBackend) Unindex(record interface{}, res *Resource, context *qor.Context) error {
+	scope, field, err := res.searchPrimaryField()
+	if err != nil {
+		return err
+	}
+
+	recordID, err := res.searchRecordID(record)
+	if err != nil {
+		return err
+	}
+	return context.GetDB().Exec(fmt.Sprintf("UPDATE %v SET %v = NULL WHERE %v = ?", scope.Table, backend.column(), field.DBName), recordID).Error
+}
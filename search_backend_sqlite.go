@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/simonedbarber/qor"
+	"gorm.io/gorm"
+)
+
+// SQLiteFTS5SearchBackend full-text search backend with SQLite's FTS5 extension, search documents are saved in a FTS5 table named `<table>_fts`, results are ranked with bm25
+type SQLiteFTS5SearchBackend struct {
+	// Tokenizer FTS5 tokenizer, default is `unicode61 remove_diacritics 2`
+	Tokenizer string
+	// PrefixMatch match terms with prefix, e.g: `prod` matches `product`
+	PrefixMatch bool
+}
+
+func (backend *SQLiteFTS5SearchBackend) tableName(res *Resource) string {
+	scope, _, _ := res.searchPrimaryField()
+	return scope.Table + "_fts"
+}
+
+// Migrate create FTS5 table for the resource
+func (backend *SQLiteFTS5SearchBackend) Migrate(res *Resource, db *gorm.DB) error {
+	if _, _, err := res.searchPrimaryField(); err != nil {
+		return err
+	}
+
+	tokenizer := backend.Tokenizer
+	if tokenizer == "" {
+		tokenizer = "unicode61 remove_diacritics 2"
+	}
+
+	return db.Exec(fmt.Sprintf(
+		"CREATE VIRTUAL TABLE IF NOT EXISTS %v USING fts5(record_id UNINDEXED, document, tokenize = '%v')",
+		backend.tableName(res), strings.Replace(tokenizer, "'", "''", -1),
+	)).Error
+}
+
+// Search filter records match keyword, ordered by bm25 rank
+func (backend *SQLiteFTS5SearchBackend) Search(keyword string, res *Resource, context *qor.Context) *gorm.DB {
+	db := context.GetDB()
+	scope, field, err := res.searchPrimaryField()
+	if err != nil {
+		context.AddError(err)
+		return db
+	}
+
+	query := sqliteFTS5Query(keyword, backend.PrefixMatch)
+	if query == "" {
+		return db
+	}
+
+	table := backend.tableName(res)
+	return db.Joins(
+		fmt.Sprintf("JOIN (SELECT record_id, bm25(%v) AS search_rank FROM %v WHERE %v MATCH ?) AS search_results ON search_results.record_id = %v.%v", table, table, table, scope.Table, field.DBName),
+		query,
+	).Order("search_results.search_rank")
+}
+
+// Index replace search document of the record
+func (backend *SQLiteFTS5SearchBackend) Index(record interface{}, res *Resource, context *qor.Context) error {
+	primaryValue, err := res.searchRecordID(record)
+	if err != nil {
+		return err
+	}
+
+	// record id is saved as text, so primary keys of any type could be indexed
+	var (
+		recordID = fmt.Sprint(primaryValue)
+		table    = backend.tableName(res)
+	)
+
+	return context.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %v WHERE record_id = ?", table), recordID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("INSERT INTO %v (record_id, document) VALUES (?, ?)", table), recordID, searchDocument(record, res.searchColumns())).Error
+	})
+}
+
+// Unindex delete search document of the record
+func (backend *SQLiteFTS5SearchBackend) Unindex(record interface{}, res *Resource, context *qor.Context) error {
+	recordID, err := res.searchRecordID(record)
+	if err != nil {
+		return err
+	}
+	return context.GetDB().Exec(fmt.Sprintf("DELETE FROM %v WHERE record_id = ?", backend.tableName(res)), fmt.Sprint(recordID)).Error
+}
+
+// sqliteFTS5Query convert keyword to FTS5 query, every term is quoted so FTS5 operators in keyword are matched as text, terms are combined with AND
+func sqliteFTS5Query(keyword string, prefixMatch bool) string {
+	var terms []string
+	for _, term := range strings.FieldsFunc(keyword, func(r rune) bool { return unicode.IsSpace(r) || r == '"' }) {
+		term = `"` + term + `"`
+		if prefixMatch {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
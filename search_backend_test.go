@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/simonedbarber/qor"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type searchDocumentCategory struct {
+	Name string
+}
+
+type searchDocumentTag struct {
+	Name string
+}
+
+type searchDocumentProduct struct {
+	Name        string
+	Code        string
+	Price       float64
+	PublishedAt *time.Time
+	Category    *searchDocumentCategory
+	Tags        []searchDocumentTag
+}
+
+func TestSearchDocument(t *testing.T) {
+	publishedAt := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	product := &searchDocumentProduct{
+		Name:        "Blue Jeans",
+		Code:        "J-01",
+		Price:       9.5,
+		PublishedAt: &publishedAt,
+		Category:    &searchDocumentCategory{Name: "Pants"},
+		Tags:        []searchDocumentTag{{Name: "denim"}, {Name: "summer"}},
+	}
+
+	document := searchDocument(product, []string{"Name", "Code", "Price", "PublishedAt", "Category.Name", "Tags.Name", "Unknown"})
+	if document != "Blue Jeans J-01 9.5 2024-03-05 Pants denim summer" {
+		t.Errorf("unexpected search document: %v", document)
+	}
+
+	if document := searchDocument(&searchDocumentProduct{Name: "Shirt"}, []string{"Name", "Code", "Category.Name"}); document != "Shirt" {
+		t.Errorf("blank values should be skipped, got %v", document)
+	}
+}
+
+func TestSQLiteFTS5Query(t *testing.T) {
+	if query := sqliteFTS5Query(`blue "jeans" OR NEAR(x`, false); query != `"blue" "jeans" "OR" "NEAR(x"` {
+		t.Errorf("unexpected FTS5 query: %v", query)
+	}
+
+	if query := sqliteFTS5Query("blue jea", true); query != `"blue"* "jea"*` {
+		t.Errorf("unexpected FTS5 prefix query: %v", query)
+	}
+
+	if query := sqliteFTS5Query(`  " `, true); query != "" {
+		t.Errorf("blank keyword should be converted to blank query, got %v", query)
+	}
+}
+
+type searchBackendProduct struct {
+	gorm.Model
+	Name string
+}
+
+func TestSQLiteFTS5SearchBackend(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:search_backend?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if err := db.Exec("CREATE VIRTUAL TABLE IF NOT EXISTS fts5_check USING fts5(document)").Error; err != nil {
+		t.Skipf("SQLite isn't built with FTS5, run tests with -tags sqlite_fts5: %v", err)
+	}
+	db.AutoMigrate(&searchBackendProduct{})
+
+	var (
+		Admin   = New(&AdminConfig{DB: db})
+		product = Admin.AddResource(&searchBackendProduct{})
+		context = &qor.Context{Config: &qor.Config{DB: db}}
+	)
+	product.SearchAttrs("Name")
+	product.UseSearchBackend(&SQLiteFTS5SearchBackend{PrefixMatch: true})
+
+	search := func(keyword string) (names []string) {
+		var results []searchBackendProduct
+		if err := product.SearchHandler(keyword, context).Find(&results).Error; err != nil {
+			t.Errorf("failed to search %v, got %v", keyword, err)
+		}
+
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+		return names
+	}
+
+	jeans, shirt := &searchBackendProduct{Name: "Blue Jeans"}, &searchBackendProduct{Name: "Blue Shirt"}
+	for _, record := range []*searchBackendProduct{jeans, shirt} {
+		if err := product.CallSave(record, context); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if names := search("jea"); !reflect.DeepEqual(names, []string{"Blue Jeans"}) {
+		t.Errorf("saved records should be indexed, got %v", names)
+	}
+
+	jeans.Name = "Black Jeans"
+	if err := product.CallSave(jeans, context); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := search("blue"); !reflect.DeepEqual(names, []string{"Blue Shirt"}) {
+		t.Errorf("updated records should be reindexed, got %v", names)
+	}
+
+	deleteCtx := context.Clone()
+	deleteCtx.ResourceID = fmt.Sprint(shirt.ID)
+	if err := product.CallDelete(&searchBackendProduct{}, deleteCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := search("shirt"); len(names) != 0 {
+		t.Errorf("deleted records should be unindexed, got %v", names)
+	}
+
+	// search documents are saved in caller's transaction, they are rolled back with the records
+	db.Transaction(func(tx *gorm.DB) error {
+		txCtx := context.Clone()
+		txCtx.SetDB(tx)
+		if err := product.CallSave(&searchBackendProduct{Name: "Red Hat"}, txCtx); err != nil {
+			t.Error(err)
+		}
+		return errors.New("rollback")
+	})
+
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM search_backend_products_fts WHERE search_backend_products_fts MATCH ?", `"hat"`).Scan(&count)
+	if count != 0 {
+		t.Errorf("search documents of rolled back records should be rolled back, got %v documents", count)
+	}
+}
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/simonedbarber/qor/utils"
+)
+
+const (
+	// DefaultSearchCenterTimeout default of AdminConfig.SearchCenterTimeout
+	DefaultSearchCenterTimeout = 2 * time.Second
+	// DefaultSearchCenterLimit default of AdminConfig.SearchCenterLimit
+	DefaultSearchCenterLimit = 5
+)
+
+// errSearchCenterTimeout returned if searching a resource exceeds AdminConfig.SearchCenterTimeout
+var errSearchCenterTimeout = errors.New("search timed out")
+
+// searchCenterResult search results of a resource in search center
+type searchCenterResult struct {
+	Context  *Context
+	Resource *Resource
+	Results  interface{}
+	// HasMore there are more results than AdminConfig.SearchCenterLimit
+	HasMore bool
+	// TimedOut searching exceeds AdminConfig.SearchCenterTimeout
+	TimedOut bool
+	// SeeAllURL url of resource's index page filtered with the keyword
+	SeeAllURL string
+	Error     error
+}
+
+// searchCenterResults search resources concurrently, every resource is searched with its own time budget, only the top AdminConfig.SearchCenterLimit results are found without counting
+func (context *Context) searchCenterResults() []*searchCenterResult {
+	var (
+		resourceName = context.Request.URL.Query().Get("resource_name")
+		keyword      = context.Request.URL.Query().Get("keyword")
+		results      []*searchCenterResult
+		channels     []chan *searchCenterResult
+	)
+
+	for _, res := range context.GetSearchableResources() {
+		ctx := context.clone()
+		ctx.Context = context.Context.Clone()
+		ctx.setResource(res)
+
+		// resources are searched concurrently, so each of them has its own settings, which are written when searching, e.g: cached index settings
+		ctx.Settings = map[string]interface{}{}
+		for key, value := range context.Settings {
+			ctx.Settings[key] = value
+		}
+
+		result := &searchCenterResult{Context: ctx, Resource: res}
+		result.SeeAllURL, _ = utils.PatchURL(ctx.URLFor(res), "keyword", keyword)
+		results = append(results, result)
+
+		if resourceName != "" && res.ToParam() != resourceName {
+			channels = append(channels, nil)
+			continue
+		}
+
+		channel := make(chan *searchCenterResult, 1)
+		channels = append(channels, channel)
+
+		go func(result searchCenterResult) {
+			defer func() {
+				if r := recover(); r != nil {
+					result.Error = fmt.Errorf("%v", r)
+					channel <- &result
+				}
+			}()
+
+			result.Results, result.HasMore, result.Error = result.Context.searchCenterFind()
+			channel <- &result
+		}(*result)
+	}
+
+	// resources are searched at the same time, so they share one deadline
+	deadline := time.NewTimer(context.Admin.SearchCenterTimeout)
+	defer deadline.Stop()
+
+	expired := false
+	for idx, channel := range channels {
+		if channel == nil {
+			continue
+		}
+
+		if !expired {
+			select {
+			case results[idx] = <-channel:
+				continue
+			case <-deadline.C:
+				expired = true
+			}
+		}
+
+		select {
+		case results[idx] = <-channel:
+		default:
+			results[idx].TimedOut, results[idx].Error = true, errSearchCenterTimeout
+		}
+	}
+	return results
+}
+
+// searchCenterFind find top results of current resource with keyword, default scopes and sorting, the query is canceled when exceeding AdminConfig.SearchCenterTimeout
+func (context *Context) searchCenterFind() (interface{}, bool, error) {
+	timeoutCtx, cancel := stdcontext.WithTimeout(stdcontext.Background(), context.Admin.SearchCenterTimeout)
+	defer cancel()
+
+	var (
+		res      = context.Resource
+		searcher = &Searcher{Context: context}
+		qorCtx   = context.Context.Clone()
+		results  = res.NewSlice()
+		limit    = context.Admin.SearchCenterLimit
+	)
+
+	qorCtx.SetDB(qorCtx.GetDB().WithContext(timeoutCtx))
+	searcher.filterData(qorCtx, true)
+	qorCtx.SetDB(qorCtx.GetDB().Limit(limit + 1))
+
+	if qorCtx.HasError() {
+		return results, false, qorCtx.Errors
+	}
+
+	if err := res.CallFindMany(results, qorCtx); err != nil {
+		if errors.Is(timeoutCtx.Err(), stdcontext.DeadlineExceeded) {
+			return results, false, errSearchCenterTimeout
+		}
+		return results, false, err
+	}
+
+	// trim the probe record that used to check if there are more results
+	records := reflect.ValueOf(results).Elem()
+	if hasMore := records.Len() > limit; hasMore {
+		records.Set(records.Slice(0, limit))
+		return results, true, nil
+	}
+	return results, false, nil
+}
+
+// searchCenterJSON convert search center results to JSON for typeahead
+func (context *Context) searchCenterJSON(results []*searchCenterResult) interface{} {
+	type record struct {
+		ID    interface{}
+		Title string
+		URL   string
+	}
+
+	type resourceResult struct {
+		Resource  string
+		Label     string
+		SeeAllURL string
+		HasMore   bool
+		TimedOut  bool
+		Error     string `json:",omitempty"`
+		Results   []record
+	}
+
+	var resourceResults = []resourceResult{}
+	for _, result := range results {
+		res := result.Resource
+		resourceResult := resourceResult{
+			Resource:  res.ToParam(),
+			Label:     string(context.t(fmt.Sprintf("qor_admin.search_center.%v", res.ToParam()), res.Name)),
+			SeeAllURL: result.SeeAllURL,
+			HasMore:   result.HasMore,
+			TimedOut:  result.TimedOut,
+			Results:   []record{},
+		}
+
+		if result.Error != nil {
+			resourceResult.Error = result.Error.Error()
+		}
+
+		if result.Results != nil {
+			records := reflect.Indirect(reflect.ValueOf(result.Results))
+			for i := 0; i < records.Len(); i++ {
+				value := records.Index(i).Interface()
+				resourceResult.Results = append(resourceResult.Results, record{
+					ID:    result.Context.primaryKeyOf(value),
+					Title: utils.Stringify(value),
+					URL:   result.Context.URLFor(value, res),
+				})
+			}
+		}
+		resourceResults = append(resourceResults, resourceResult)
+	}
+	return resourceResults
+}
@@ -0,0 +1,110 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/simonedbarber/admin"
+	. "github.com/simonedbarber/admin/tests/dummy"
+	"github.com/simonedbarber/qor"
+	"gorm.io/gorm"
+)
+
+type searchCenterResource struct {
+	Resource string
+	HasMore  bool
+	TimedOut bool
+	Results  []struct{ Title string }
+}
+
+func newSearchCenterServer(config *admin.AdminConfig) *httptest.Server {
+	searchAdmin := admin.New(config)
+	users := searchAdmin.AddResource(&User{})
+	users.SearchHandler = func(keyword string, context *qor.Context) *gorm.DB {
+		time.Sleep(500 * time.Millisecond)
+		return context.GetDB()
+	}
+
+	companies := searchAdmin.AddResource(&Company{})
+	companies.SearchAttrs("Name")
+	searchAdmin.AddSearchResource(users, companies)
+	return httptest.NewServer(searchAdmin.NewServeMux("/admin"))
+}
+
+func searchCenter(t *testing.T, server *httptest.Server, keyword string) map[string]searchCenterResource {
+	req, _ := http.NewRequest("GET", server.URL+"/admin/!search?keyword="+keyword, nil)
+	req.Header.Set("Accept", "application/json")
+
+	results := map[string]searchCenterResource{}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err)
+		return results
+	}
+	defer resp.Body.Close()
+
+	var resources []searchCenterResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		t.Error(err)
+	}
+
+	for _, resource := range resources {
+		results[resource.Resource] = resource
+	}
+	return results
+}
+
+func TestSearchCenterTimeoutAndLimit(t *testing.T) {
+	db.Save(&Company{Name: "search center company 1"})
+	db.Save(&Company{Name: "search center company 2"})
+
+	server := newSearchCenterServer(&admin.AdminConfig{Auth: DummyAuth{}, DB: db, SearchCenterTimeout: 100 * time.Millisecond, SearchCenterLimit: 1})
+	defer server.Close()
+
+	results := searchCenter(t, server, "search+center")
+	if users := results["users"]; !users.TimedOut {
+		t.Errorf("slow search of users should be timed out, got %+v", users)
+	}
+
+	if companies := results["companies"]; companies.TimedOut || !companies.HasMore || len(companies.Results) != 1 {
+		t.Errorf("companies should be limited to 1 result and have more, got %+v", companies)
+	}
+}
+
+func TestSearchCenterConcurrentSearches(t *testing.T) {
+	db.Save(&Company{Name: "concurrent search company"})
+
+	// admins with different configurations search at the same time, resources of a request are searched concurrently with their own settings, run with -race
+	var (
+		wg      sync.WaitGroup
+		servers = []*httptest.Server{
+			newSearchCenterServer(&admin.AdminConfig{Auth: DummyAuth{}, DB: db, SearchCenterTimeout: 50 * time.Millisecond}),
+			newSearchCenterServer(&admin.AdminConfig{Auth: DummyAuth{}, DB: db, SearchCenterTimeout: time.Second}),
+		}
+	)
+
+	for idx, server := range servers {
+		defer server.Close()
+
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(idx int, server *httptest.Server) {
+				defer wg.Done()
+
+				results := searchCenter(t, server, "concurrent")
+				if timedOut := results["users"].TimedOut; timedOut != (idx == 0) {
+					t.Errorf("users of admin %v should be timed out: %v, got %v", idx, idx == 0, timedOut)
+				}
+
+				if companies := results["companies"]; len(companies.Results) != 1 {
+					t.Errorf("companies of admin %v should be found, got %+v", idx, companies)
+				}
+			}(idx, server)
+		}
+	}
+	wg.Wait()
+}
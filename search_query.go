@@ -0,0 +1,538 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/simonedbarber/qor/utils"
+)
+
+// SearchQuery parsed search query, search query is enabled with resource's Config.QueryLanguage, e.g:
+//
+//	status:paid total>100 created:2024-01..2024-03 -country:US "free text"
+//
+// supported conditions:
+//
+//	name:value       equal
+//	name:value*      start with
+//	name:*value      end with
+//	name:*value*     contains
+//	name>value       greater than, also >=, <, <=
+//	name:start..end  between, for datetime filters, `name:value` means the whole period of value
+//	-name:value      negate the condition
+//	"free text"      keyword, words not in conditions are keyword too
+type SearchQuery struct {
+	Keyword    string
+	Conditions []*SearchCondition
+	Errors     []*SearchQueryError
+
+	// rawKeyword keyword in search query syntax, quoted free text keeps its quotes, so it is parsed as keyword again
+	rawKeyword string
+}
+
+// SearchCondition a condition of search query, it is a registered filter with its values
+type SearchCondition struct {
+	Filter    *Filter
+	Not       bool
+	Operation string
+	Value     string
+	Start     string
+	End       string
+}
+
+// SearchQueryError search query parse error, Position is the offset of the wrong part in the query
+type SearchQueryError struct {
+	Position int
+	Token    string
+	Message  string
+}
+
+// Error return error message
+func (err *SearchQueryError) Error() string {
+	return fmt.Sprintf("%v (%v)", err.Message, err.Token)
+}
+
+// searchQueryOperators query operators to filter operations, longer operators go first
+var searchQueryOperators = []struct {
+	Operator  string
+	Operation string
+}{
+	{">=", "gteq"},
+	{"<=", "lteq"},
+	{"!=", "ne"},
+	{">", "gt"},
+	{"<", "lt"},
+	{":", "eq"},
+}
+
+// searchQueryToken a term of search query
+type searchQueryToken struct {
+	Position int
+	Text     string
+	Quoted   bool
+}
+
+// tokenizeSearchQuery split query by spaces, quoted text is kept in one token
+func tokenizeSearchQuery(query string) (tokens []searchQueryToken, errs []*SearchQueryError) {
+	var (
+		current  strings.Builder
+		start    = -1
+		inQuote  bool
+		quoted   bool
+		addToken = func() {
+			if start >= 0 {
+				tokens = append(tokens, searchQueryToken{Position: start, Text: current.String(), Quoted: quoted})
+			}
+			current.Reset()
+			start, quoted = -1, false
+		}
+	)
+
+	for idx, r := range query {
+		switch {
+		case r == '"':
+			if start < 0 {
+				start = idx
+			}
+			inQuote, quoted = !inQuote, true
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			addToken()
+		default:
+			if start < 0 {
+				start = idx
+			}
+			current.WriteRune(r)
+		}
+	}
+
+	if inQuote {
+		errs = append(errs, &SearchQueryError{Position: start, Token: current.String(), Message: "unterminated quote"})
+	}
+	addToken()
+	return
+}
+
+// unquoteSearchQuery remove quotes of a term
+func unquoteSearchQuery(str string) string {
+	if len(str) >= 2 && strings.HasPrefix(str, `"`) && strings.HasSuffix(str, `"`) {
+		str = str[1 : len(str)-1]
+	}
+	return strings.Replace(str, `"`, "", -1)
+}
+
+// quoteSearchQuery quote a value if it includes spaces
+func quoteSearchQuery(str string) string {
+	if strings.ContainsAny(str, " \t\"") {
+		return `"` + strings.Replace(str, `"`, "", -1) + `"`
+	}
+	return str
+}
+
+// searchQueryFilter find filter with its name in query, could be filter's name, param name, label, or param name without `_at`, e.g: `created` for `CreatedAt`
+func (res *Resource) searchQueryFilter(name string) *Filter {
+	name = strings.ToLower(name)
+	for _, filter := range res.filters {
+		param := utils.ToParamString(filter.Name)
+		if name == strings.ToLower(filter.Name) || name == param || name == strings.TrimSuffix(param, "_at") || name == utils.ToParamString(filter.Label) {
+			return filter
+		}
+	}
+	return nil
+}
+
+// isSearchQueryName check if text is a valid filter name in query
+func isSearchQueryName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for idx, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || (idx > 0 && (unicode.IsDigit(r) || r == '.'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDatetimeFilter check if filter filters with Start, End
+func isDatetimeFilter(filter *Filter) bool {
+	return filter.Type == "datetime" || filter.Type == "date"
+}
+
+// ParseSearchQuery parse search query to registered filters and keyword, wrong parts will be reported in query's Errors and ignored
+func (res *Resource) ParseSearchQuery(query string) *SearchQuery {
+	var (
+		result       = &SearchQuery{}
+		keywords     []string
+		rawKeywords  []string
+		tokens, errs = tokenizeSearchQuery(query)
+	)
+	result.Errors = append(result.Errors, errs...)
+
+	for _, token := range tokens {
+		text := token.Text
+
+		// free text
+		if strings.HasPrefix(text, `"`) {
+			if keyword := unquoteSearchQuery(text); keyword != "" {
+				keywords = append(keywords, keyword)
+				rawKeywords = append(rawKeywords, `"`+keyword+`"`)
+			}
+			continue
+		}
+
+		condition := &SearchCondition{}
+		if strings.HasPrefix(text, "-") && len(text) > 1 {
+			condition.Not = true
+			text = text[1:]
+		}
+
+		var name, operator, value string
+		for _, o := range searchQueryOperators {
+			if idx := strings.Index(text, o.Operator); idx > 0 && (operator == "" || idx < len(name)) {
+				name, operator, value = text[:idx], o.Operator, text[idx+len(o.Operator):]
+				condition.Operation = o.Operation
+			}
+		}
+
+		if operator == "" || !isSearchQueryName(name) {
+			keyword := unquoteSearchQuery(token.Text)
+			keywords = append(keywords, keyword)
+			if token.Quoted {
+				keyword = `"` + keyword + `"`
+			}
+			rawKeywords = append(rawKeywords, keyword)
+			continue
+		}
+
+		addError := func(message string) {
+			result.Errors = append(result.Errors, &SearchQueryError{Position: token.Position, Token: token.Text, Message: message})
+		}
+
+		if condition.Filter = res.searchQueryFilter(name); condition.Filter == nil {
+			addError(fmt.Sprintf("unknown filter %v", name))
+			continue
+		}
+
+		if value = unquoteSearchQuery(value); value == "" {
+			addError(fmt.Sprintf("missing value for %v", name))
+			continue
+		}
+
+		if condition.Operation == "ne" {
+			condition.Not, condition.Operation = !condition.Not, "eq"
+		}
+
+		if strings.Contains(value, "..") {
+			if condition.Operation != "eq" {
+				addError("range could only be used with `:`")
+				continue
+			}
+
+			values := strings.SplitN(value, "..", 2)
+			condition.Operation, condition.Start, condition.End = "", values[0], values[1]
+			if condition.Start == "" && condition.End == "" {
+				addError(fmt.Sprintf("missing range for %v", name))
+				continue
+			}
+		} else if isDatetimeFilter(condition.Filter) {
+			switch condition.Operation {
+			case "gt", "gteq":
+				condition.Start = value
+			case "lt", "lteq":
+				condition.End = value
+			default:
+				condition.Start, condition.End = value, value
+			}
+			condition.Operation = ""
+		} else {
+			if condition.Operation == "eq" {
+				switch {
+				case len(value) > 2 && strings.HasPrefix(value, "*") && strings.HasSuffix(value, "*"):
+					condition.Operation, value = "conts", strings.Trim(value, "*")
+				case len(value) > 1 && strings.HasSuffix(value, "*"):
+					condition.Operation, value = "start_with", strings.TrimSuffix(value, "*")
+				case len(value) > 1 && strings.HasPrefix(value, "*"):
+					condition.Operation, value = "end_with", strings.TrimPrefix(value, "*")
+				}
+			}
+			condition.Value = value
+		}
+
+		result.Conditions = append(result.Conditions, condition)
+	}
+
+	result.Keyword, result.rawKeyword = strings.Join(keywords, " "), strings.Join(rawKeywords, " ")
+	return result
+}
+
+// values return meta values of condition that used by filter
+func (condition *SearchCondition) values() map[string]interface{} {
+	values := map[string]interface{}{}
+	if condition.Start != "" || condition.End != "" {
+		// datetime filters filter range with Start, End, others use Min, Max
+		startKey, endKey := "Start", "End"
+		if !isDatetimeFilter(condition.Filter) {
+			startKey, endKey = "Min", "Max"
+		}
+
+		if condition.Start != "" {
+			values[startKey] = condition.Start
+		}
+		if condition.End != "" {
+			values[endKey] = condition.End
+		}
+	} else {
+		values["Value"] = condition.Value
+		values["Operation"] = condition.Operation
+	}
+	return values
+}
+
+// FilterGroup convert conditions to a filter group
+func (query *SearchQuery) FilterGroup() *FilterGroup {
+	group := &FilterGroup{}
+	for _, condition := range query.Conditions {
+		group.Groups = append(group.Groups, &FilterGroup{Filter: condition.Filter.Name, Not: condition.Not, Values: condition.values()})
+	}
+	return group
+}
+
+// URLValues convert search query to url params that used by the advanced filter, keyword keeps quotes of free text, e.g:
+//
+//	status:paid -country:US "free text"
+//	=> keyword="free text"&filters[Status].Value=paid&filters[Status].Operation=eq&filter_group={"groups":[{"not":true,"filter":"Country","values":{"Operation":"eq","Value":"US"}}]}
+func (query *SearchQuery) URLValues() url.Values {
+	var (
+		values = url.Values{}
+		group  = &FilterGroup{}
+	)
+
+	if query.rawKeyword != "" {
+		values.Set("keyword", query.rawKeyword)
+	} else if query.Keyword != "" {
+		values.Set("keyword", query.Keyword)
+	}
+
+	for _, condition := range query.Conditions {
+		prefix := fmt.Sprintf("filters[%v].", condition.Filter.Name)
+		if condition.Not || values.Get(prefix+"Value") != "" || values.Get(prefix+"Start") != "" || values.Get(prefix+"End") != "" || values.Get(prefix+"Min") != "" || values.Get(prefix+"Max") != "" {
+			// negated and repeated conditions could only be represented with filter group
+			group.Groups = append(group.Groups, &FilterGroup{Filter: condition.Filter.Name, Not: condition.Not, Values: condition.values()})
+			continue
+		}
+
+		for key, value := range condition.values() {
+			if str := fmt.Sprint(value); str != "" {
+				values.Set(prefix+key, str)
+			}
+		}
+	}
+
+	if !group.IsBlank() {
+		values.Set(FilterGroupParam, group.String())
+	}
+	return values
+}
+
+// formatSearchCondition format filter's values to a search query condition
+func formatSearchCondition(filter *Filter, not bool, values map[string]string) string {
+	var (
+		name      = utils.ToParamString(filter.Name)
+		value     = values["Value"]
+		start     = values["Start"]
+		end       = values["End"]
+		condition string
+	)
+
+	if preset := values["Preset"]; preset != "" {
+		start, end = preset, preset
+	}
+
+	if values["Min"] != "" || values["Max"] != "" {
+		start, end = values["Min"], values["Max"]
+	}
+
+	switch {
+	case start != "" && start == end:
+		condition = name + ":" + quoteSearchQuery(start)
+	case start != "" && end != "":
+		condition = name + ":" + quoteSearchQuery(start) + ".." + quoteSearchQuery(end)
+	case start != "":
+		condition = name + ">=" + quoteSearchQuery(start)
+	case end != "":
+		condition = name + "<=" + quoteSearchQuery(end)
+	case value != "":
+		switch values["Operation"] {
+		case "gt":
+			condition = name + ">" + quoteSearchQuery(value)
+		case "ne":
+			condition = name + "!=" + quoteSearchQuery(value)
+		case "gteq":
+			condition = name + ">=" + quoteSearchQuery(value)
+		case "lt":
+			condition = name + "<" + quoteSearchQuery(value)
+		case "lteq":
+			condition = name + "<=" + quoteSearchQuery(value)
+		case "start_with":
+			condition = name + ":" + quoteSearchQuery(value+"*")
+		case "end_with":
+			condition = name + ":" + quoteSearchQuery("*"+value)
+		case "eq", "equal":
+			condition = name + ":" + quoteSearchQuery(value)
+		default:
+			condition = name + ":" + quoteSearchQuery("*"+value+"*")
+		}
+	default:
+		return ""
+	}
+
+	if not {
+		return "-" + condition
+	}
+	return condition
+}
+
+// flatFilterGroup return conditions of filter group if it only includes conditions combined with AND, which could be represented with search query
+func flatFilterGroup(group *FilterGroup) ([]*FilterGroup, bool) {
+	if group.Not || group.Filter != "" || group.Operator == FilterGroupOr {
+		return nil, false
+	}
+
+	for _, g := range group.Groups {
+		if g.Filter == "" {
+			return nil, false
+		}
+	}
+	return group.Groups, true
+}
+
+// FormatSearchQuery format url params of the advanced filter to search query, it is the reverse of SearchQuery's URLValues
+func (res *Resource) FormatSearchQuery(values url.Values) string {
+	var (
+		conditions []string
+		names      []string
+		filters    = map[string]map[string]string{}
+	)
+
+	for key := range values {
+		if matches := filterRegexp.FindStringSubmatch(key); len(matches) > 0 {
+			if filters[matches[1]] == nil {
+				filters[matches[1]] = map[string]string{}
+				names = append(names, matches[1])
+			}
+			filters[matches[1]][strings.TrimPrefix(key, fmt.Sprintf("filters[%v].", matches[1]))] = values.Get(key)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if filter := res.GetFilter(name); filter != nil {
+			if condition := formatSearchCondition(filter, false, filters[name]); condition != "" {
+				conditions = append(conditions, condition)
+			}
+		}
+	}
+
+	if str := values.Get(FilterGroupParam); str != "" {
+		if group, err := ParseFilterGroup(str); err == nil {
+			if groups, ok := flatFilterGroup(group); ok {
+				for _, g := range groups {
+					if filter := res.GetFilter(g.Filter); filter != nil {
+						metaValues := map[string]string{}
+						for key, value := range g.Values {
+							metaValues[key] = fmt.Sprint(value)
+						}
+
+						if condition := formatSearchCondition(filter, g.Not, metaValues); condition != "" {
+							conditions = append(conditions, condition)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// keyword is in search query syntax already, e.g: "free text" more
+	if keyword := values.Get("keyword"); keyword != "" {
+		conditions = append(conditions, keyword)
+	}
+	return strings.Join(conditions, " ")
+}
+
+// searchQueryURL return url with params converted from search query in keyword, used to redirect search box's query to the advanced filter's params
+func (context *Context) searchQueryURL() (string, bool) {
+	if context.Resource == nil || !context.Resource.Config.QueryLanguage || context.Request.Method != "GET" {
+		return "", false
+	}
+
+	keyword := context.Request.URL.Query().Get("keyword")
+	if keyword == "" {
+		return "", false
+	}
+
+	query := context.Resource.ParseSearchQuery(keyword)
+	if len(query.Errors) > 0 || len(query.Conditions) == 0 {
+		return "", false
+	}
+
+	values := context.searchQueryHiddenParams()
+	for key, value := range query.URLValues() {
+		values[key] = value
+	}
+
+	requestURL := *context.Request.URL
+	requestURL.RawQuery = values.Encode()
+	return requestURL.String(), true
+}
+
+// searchQueryText return search box's text, format current advanced filter as search query if query language is enabled
+func (context *Context) searchQueryText() string {
+	query := context.Request.URL.Query()
+	if context.Resource == nil || !context.Resource.Config.QueryLanguage {
+		return query.Get("keyword")
+	}
+
+	if keyword := query.Get("keyword"); keyword != "" && context.Searcher.searchQuery != nil && len(context.Searcher.searchQuery.Errors) > 0 {
+		return keyword
+	}
+	return context.Resource.FormatSearchQuery(query)
+}
+
+// searchQueryErrors return parse errors of search query
+func (context *Context) searchQueryErrors() []*SearchQueryError {
+	if context.Searcher == nil || context.Searcher.searchQuery == nil {
+		return nil
+	}
+	return context.Searcher.searchQuery.Errors
+}
+
+// searchQueryHiddenParams return params that should be kept when submitting search box, params represented in search query are excluded
+func (context *Context) searchQueryHiddenParams() url.Values {
+	values := url.Values{}
+	for key, value := range context.Request.URL.Query() {
+		if key == "keyword" || key == "page" || key == "cursor" {
+			continue
+		}
+
+		if context.Resource != nil && context.Resource.Config.QueryLanguage {
+			if filterRegexp.MatchString(key) {
+				continue
+			}
+
+			if key == FilterGroupParam {
+				if group, err := ParseFilterGroup(value[0]); err == nil {
+					if _, ok := flatFilterGroup(group); ok {
+						continue
+					}
+				}
+			}
+		}
+		values[key] = value
+	}
+	return values
+}
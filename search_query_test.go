@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	res := &Resource{filters: []*Filter{
+		{Name: "Status", Label: "Status", Type: "string"},
+		{Name: "Total", Label: "Total", Type: "number"},
+		{Name: "CreatedAt", Label: "Created At", Type: "datetime"},
+		{Name: "Country", Label: "Country", Type: "string"},
+	}}
+
+	query := res.ParseSearchQuery(`status:paid total>100 created:2024-01..2024-03 -country:US "free text" more`)
+	if len(query.Errors) != 0 {
+		t.Fatalf("query should be parsed without errors, but got %v", query.Errors)
+	}
+
+	if query.Keyword != "free text more" {
+		t.Errorf("keyword should be free text, but got %v", query.Keyword)
+	}
+
+	expects := []SearchCondition{
+		{Operation: "eq", Value: "paid"},
+		{Operation: "gt", Value: "100"},
+		{Start: "2024-01", End: "2024-03"},
+		{Not: true, Operation: "eq", Value: "US"},
+	}
+
+	if len(query.Conditions) != len(expects) {
+		t.Fatalf("query should have %v conditions, but got %v", len(expects), len(query.Conditions))
+	}
+
+	for idx, expect := range expects {
+		condition := query.Conditions[idx]
+		expect.Filter = res.filters[idx]
+		if *condition != expect {
+			t.Errorf("condition #%v should be %+v, but got %+v", idx, expect, *condition)
+		}
+	}
+
+	values := query.URLValues()
+	if values.Get("filters[Status].Value") != "paid" || values.Get("filters[Total].Operation") != "gt" || values.Get("filters[CreatedAt].Start") != "2024-01" || values.Get(FilterGroupParam) == "" {
+		t.Errorf("search query should be converted to filter params, but got %v", values)
+	}
+
+	formatted := res.FormatSearchQuery(values)
+	if reparsed := res.ParseSearchQuery(formatted); len(reparsed.Errors) != 0 || reparsed.URLValues().Encode() != values.Encode() {
+		t.Errorf("search query %v should round trip, but got %v", formatted, reparsed.URLValues())
+	}
+}
+
+func TestSearchQueryQuotedKeyword(t *testing.T) {
+	res := &Resource{filters: []*Filter{{Name: "Status", Label: "Status", Type: "string"}}}
+
+	values := res.ParseSearchQuery(`status:paid "status:draft" more`).URLValues()
+	if keyword := values.Get("keyword"); keyword != `"status:draft" more` {
+		t.Errorf("keyword should keep quotes of free text, but got %v", keyword)
+	}
+
+	if query := res.ParseSearchQuery(values.Get("keyword")); len(query.Conditions) != 0 || query.Keyword != "status:draft more" {
+		t.Errorf("quoted keyword should be parsed as keyword again, but got %+v", query)
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	res := &Resource{filters: []*Filter{{Name: "Status", Label: "Status", Type: "string"}}}
+
+	for _, str := range []string{`unknown:value`, `status:`, `status>1..2`, `status:"unterminated`} {
+		if query := res.ParseSearchQuery(str); len(query.Errors) == 0 {
+			t.Errorf("query %v should have errors", str)
+		}
+	}
+
+	if text := res.FormatSearchQuery(url.Values{"filters[Status].Value": {"in progress"}, "filters[Status].Operation": {"start_with"}}); text != `status:"in progress*"` {
+		t.Errorf("filter params should be formatted as search query, but got %v", text)
+	}
+}
@@ -22,24 +22,48 @@ var filterRegexp = regexp.MustCompile(`^filters\[(.*?)\]`)
 // PaginationPageCount default pagination page count
 var PaginationPageCount = 20
 
+// MaxPerPageCount max page count could be saved in user's index settings
+var MaxPerPageCount = 1000
+
 // Pagination is used to hold pagination related information when rendering tables
 type Pagination struct {
 	Total       int
 	Pages       int
 	CurrentPage int
 	PerPage     int
+
+	// TotalEstimated total is estimated from database statistics
+	TotalEstimated bool
+	// TotalSkipped total hasn't been counted, Pages only includes next page if there is
+	TotalSkipped bool
+
+	// UseCursor paginate with cursors, Cursor is current page's cursor, PrevCursor, NextCursor are blank if no previous, next page
+	UseCursor  bool
+	Cursor     string
+	PrevCursor string
+	NextCursor string
 }
 
 // Searcher is used to search results
 type Searcher struct {
 	*Context
-	scopes     []*Scope
-	filters    map[*Filter]*resource.MetaValues
-	Pagination Pagination
+	scopes      []*Scope
+	filters     map[*Filter]*resource.MetaValues
+	filterGroup *FilterGroup
+	searchQuery *SearchQuery
+	facet       *facet
+	Pagination  Pagination
+
+	probeLimit    int
+	cursorColumns []cursorColumn
+	cursorBefore  bool
+
+	// facetCounts counts of filters' options in current request
+	facetCounts map[*Filter]map[string]int64
 }
 
 func (s *Searcher) clone() *Searcher {
-	return &Searcher{Context: s.Context, scopes: s.scopes, filters: s.filters}
+	return &Searcher{Context: s.Context, scopes: s.scopes, filters: s.filters, filterGroup: s.filterGroup, searchQuery: s.searchQuery, facet: s.facet}
 }
 
 // Page set current page, if current page equal -1, then show all records
@@ -78,6 +102,13 @@ func (s *Searcher) Filter(filter *Filter, values *resource.MetaValues) *Searcher
 	return newSearcher
 }
 
+// FilterGroup filter with filter group, which combines defined filters with AND/OR logic
+func (s *Searcher) FilterGroup(group *FilterGroup) *Searcher {
+	newSearcher := s.clone()
+	newSearcher.filterGroup = group
+	return newSearcher
+}
+
 // FindOne find one record based on current conditions
 func (s *Searcher) FindOne() (interface{}, error) {
 	var (
@@ -107,6 +138,9 @@ func (s *Searcher) FindMany() (interface{}, error) {
 	}
 
 	err = s.Resource.CallFindMany(result, context)
+	if err == nil {
+		s.paginateResult(result)
+	}
 	return result, err
 }
 
@@ -129,6 +163,10 @@ func (s *Searcher) filterData(context *qor.Context, withDefaultScope bool) *qor.
 					}
 				}
 
+				if s.facet != nil && s.facet.excludes(scope) {
+					filterWithThisScope = false
+				}
+
 				if filterWithThisScope {
 					db = scope.Handler(db, context)
 				}
@@ -155,17 +193,15 @@ func (s *Searcher) filterData(context *qor.Context, withDefaultScope bool) *qor.
 		}
 	}
 
-	// add order by
-	if orderBy := context.Request.Form.Get("order_by"); orderBy != "" {
-		if regexp.MustCompile("^[a-zA-Z_]+$").MatchString(orderBy) {
-			scope := utils.NewScope(s.Context.Resource.Value)
-			if field := scope.LookUpField(strings.TrimSuffix(orderBy, "_desc")); field != nil {
-				if strings.HasSuffix(orderBy, "_desc") {
-					db = db.Order(field.DBName + " DESC")
-				} else {
-					db = db.Order(field.DBName)
-				}
-			}
+	// call filter group
+	if !s.filterGroup.IsBlank() {
+		db = s.Resource.applyFilterGroup(db, s.filterGroup, context)
+	}
+
+	// add order by, sorting will be applied when paginating if paginate with cursors, facet counts don't need sorting
+	if orderBy := s.orderBy(context); orderBy != "" && s.facet == nil {
+		if _, ok := s.Resource.cursorColumns(orderBy); !ok {
+			db = s.Context.Resource.applyOrderBy(db, orderBy)
 		}
 	}
 
@@ -173,7 +209,9 @@ func (s *Searcher) filterData(context *qor.Context, withDefaultScope bool) *qor.
 
 	// call search
 	var keyword string
-	if keyword = context.Request.Form.Get("keyword"); keyword == "" {
+	if s.searchQuery != nil {
+		keyword = s.searchQuery.Keyword
+	} else if keyword = context.Request.Form.Get("keyword"); keyword == "" {
 		keyword = context.Request.URL.Query().Get("keyword")
 	}
 
@@ -185,6 +223,68 @@ func (s *Searcher) filterData(context *qor.Context, withDefaultScope bool) *qor.
 	return context
 }
 
+// orderBy return order by param of request, fallback to user's index settings
+func (s *Searcher) orderBy(context *qor.Context) string {
+	orderBy := context.Request.Form.Get("order_by")
+	if orderBy == "" {
+		orderBy = s.getIndexSettings().OrderBy
+	}
+	return orderBy
+}
+
+// parseRequest parse scopes, filters, filter group and search query from request
+func (s *Searcher) parseRequest(context *qor.Context) *Searcher {
+	searcher := s.clone()
+
+	// parse scopes
+	scopes := context.Request.Form["scopes"]
+	searcher = searcher.Scope(scopes...)
+
+	// parse filters
+	for key := range context.Request.Form {
+		if matches := filterRegexp.FindStringSubmatch(key); len(matches) > 0 {
+			var prefix = fmt.Sprintf("filters[%v].", matches[1])
+			for _, filter := range s.Resource.filters {
+				if filter.Name == matches[1] {
+					if metaValues, err := resource.ConvertFormToMetaValues(context.Request, []resource.Metaor{}, prefix); err == nil {
+						searcher = searcher.Filter(filter, metaValues)
+					}
+				}
+			}
+		}
+	}
+
+	// parse filter group
+	if value := context.Request.Form.Get(FilterGroupParam); value != "" {
+		group, err := ParseFilterGroup(value)
+		if err == nil {
+			err = group.Validate(s.Resource)
+		}
+
+		if err == nil {
+			searcher = searcher.FilterGroup(group)
+		} else {
+			context.AddError(err)
+		}
+	}
+
+	// parse search query
+	searcher.searchQuery = nil
+	if keyword := context.Request.Form.Get("keyword"); keyword != "" && s.Resource.Config.QueryLanguage {
+		query := s.Resource.ParseSearchQuery(keyword)
+		if len(query.Conditions) > 0 {
+			group := query.FilterGroup()
+			if searcher.filterGroup != nil {
+				group.Groups = append(group.Groups, searcher.filterGroup)
+			}
+			searcher = searcher.FilterGroup(group)
+		}
+		searcher.searchQuery = query
+	}
+
+	return searcher
+}
+
 func (s *Searcher) parseContext(withDefaultScope bool) *qor.Context {
 	var (
 		searcher = s.clone()
@@ -192,53 +292,27 @@ func (s *Searcher) parseContext(withDefaultScope bool) *qor.Context {
 	)
 
 	if context != nil && context.Request != nil {
-		// parse scopes
-		scopes := context.Request.Form["scopes"]
-		searcher = searcher.Scope(scopes...)
-
-		// parse filters
-		for key := range context.Request.Form {
-			if matches := filterRegexp.FindStringSubmatch(key); len(matches) > 0 {
-				var prefix = fmt.Sprintf("filters[%v].", matches[1])
-				for _, filter := range s.Resource.filters {
-					if filter.Name == matches[1] {
-						if metaValues, err := resource.ConvertFormToMetaValues(context.Request, []resource.Metaor{}, prefix); err == nil {
-							searcher = searcher.Filter(filter, metaValues)
-						}
-					}
-				}
-			}
-		}
+		searcher = searcher.parseRequest(context)
+		s.searchQuery = searcher.searchQuery
 
 		if savingName := context.Request.Form.Get("filter_saving_name"); savingName != "" {
-			var filters []SavedFilter
-			requestURL := context.Request.URL
-			requestURLQuery := context.Request.URL.Query()
-			requestURLQuery.Del("filter_saving_name")
+			requestURL := *context.Request.URL
+			requestURLQuery := requestURL.Query()
+			for _, key := range []string{"filter_saving_name", "filter_saving_visibility", "filter_saving_default"} {
+				requestURLQuery.Del(key)
+			}
 			requestURL.RawQuery = requestURLQuery.Encode()
-			newFilters := []SavedFilter{{Name: savingName, URL: requestURL.String()}}
-			if context.AddError(searcher.Admin.SettingsStorage.Get("saved_filters", &filters, searcher.Context)); !context.HasError() {
-				for _, filter := range filters {
-					if filter.Name != savingName {
-						newFilters = append(newFilters, filter)
-					}
-				}
 
-				context.AddError(searcher.Admin.SettingsStorage.Save("saved_filters", newFilters, searcher.Resource, context.CurrentUser, searcher.Context))
-			}
+			context.AddError(searcher.Context.saveSavedFilter(SavedFilter{
+				Name:       savingName,
+				URL:        requestURL.String(),
+				Visibility: SavedFilterVisibility(context.Request.Form.Get("filter_saving_visibility")),
+				Default:    context.Request.Form.Get("filter_saving_default") == "true",
+			}))
 		}
 
 		if savingName := context.Request.Form.Get("delete_saved_filter"); savingName != "" {
-			var filters, newFilters []SavedFilter
-			if context.AddError(searcher.Admin.SettingsStorage.Get("saved_filters", &filters, searcher.Context)); !context.HasError() {
-				for _, filter := range filters {
-					if filter.Name != savingName {
-						newFilters = append(newFilters, filter)
-					}
-				}
-
-				context.AddError(searcher.Admin.SettingsStorage.Save("saved_filters", newFilters, searcher.Resource, context.CurrentUser, searcher.Context))
-			}
+			context.AddError(searcher.Context.deleteSavedFilter(savingName))
 		}
 	}
 
@@ -247,10 +321,20 @@ func (s *Searcher) parseContext(withDefaultScope bool) *qor.Context {
 	db := context.GetDB()
 
 	// pagination
-	context.SetDB(db.Model(s.Resource.Value).Set("qor:getting_total_count", true))
-	total := int64(0)
-	s.Resource.CallFindMany(&total, context)
-	s.Pagination.Total = int(total)
+	s.probeLimit, s.cursorColumns, s.cursorBefore = 0, nil, false
+	s.Pagination.TotalEstimated, s.Pagination.TotalSkipped = false, false
+	s.Pagination.UseCursor, s.Pagination.Cursor, s.Pagination.PrevCursor, s.Pagination.NextCursor = false, "", "", ""
+
+	if s.Resource.Config.TotalCount == TotalCountSkip {
+		s.Pagination.Total, s.Pagination.TotalSkipped = 0, true
+	} else if total, ok := s.estimateTotalCount(db); ok {
+		s.Pagination.Total, s.Pagination.TotalEstimated = int(total), true
+	} else {
+		context.SetDB(db.Model(s.Resource.Value).Set("qor:getting_total_count", true))
+		total := int64(0)
+		s.Resource.CallFindMany(&total, context)
+		s.Pagination.Total = int(total)
+	}
 
 	if s.Pagination.CurrentPage == 0 {
 		if s.Context.Request != nil {
@@ -269,6 +353,8 @@ func (s *Searcher) parseContext(withDefaultScope bool) *qor.Context {
 			s.Pagination.PerPage = perPage
 		} else if all := s.Context.Request.Form.Get("per_page"); all == "all" { // added all to support kanban TODO: change this to be removal of limit in future
 			s.Pagination.PerPage = 1000000
+		} else if perPage := s.getIndexSettings().PerPage; perPage > 0 {
+			s.Pagination.PerPage = perPage
 		} else if s.Resource.Config.PageCount > 0 {
 			s.Pagination.PerPage = s.Resource.Config.PageCount
 		} else {
@@ -283,9 +369,22 @@ func (s *Searcher) parseContext(withDefaultScope bool) *qor.Context {
 		}
 	}
 
-	if s.Pagination.CurrentPage > 0 {
-		s.Pagination.Pages = (s.Pagination.Total-1)/s.Pagination.PerPage + 1
-		db = db.Limit(limit).Offset((s.Pagination.CurrentPage - 1) * s.Pagination.PerPage)
+	if columns, ok := s.Resource.cursorColumns(s.orderBy(context)); ok {
+		s.Pagination.UseCursor, s.cursorColumns = true, columns
+		db = s.paginateWithCursor(db, columns, context.Request.Form.Get("cursor"))
+		if s.Pagination.CurrentPage > 0 {
+			// query one more record to know if there is more page
+			s.probeLimit = limit
+			db = db.Limit(limit + 1)
+		}
+	} else if s.Pagination.CurrentPage > 0 {
+		if s.Pagination.TotalSkipped {
+			s.probeLimit = limit
+			db = db.Limit(limit + 1).Offset((s.Pagination.CurrentPage - 1) * s.Pagination.PerPage)
+		} else {
+			s.Pagination.Pages = (s.Pagination.Total-1)/s.Pagination.PerPage + 1
+			db = db.Limit(limit).Offset((s.Pagination.CurrentPage - 1) * s.Pagination.PerPage)
+		}
 	}
 
 	db.Set("qor:getting_total_count", false)
@@ -404,38 +503,13 @@ func filterResourceByFields(res *Resource, filterFields []filterField, keyword s
 				}
 			}
 
-			appendInteger := func(field *schema.Field) {
-				if num, err := strconv.Atoi(keyword); err == nil {
-					keywords = append(keywords, num)
-					switch filterfield.Operation {
-					case "gt":
-						conditions = append(conditions, fmt.Sprintf("%v.%v > ?", tableName, field.DBName))
-					case "lt":
-						conditions = append(conditions, fmt.Sprintf("%v.%v < ?", tableName, field.DBName))
-					case "present":
-						conditions = append(conditions, fmt.Sprintf("%v.%v IS NOT NULL", tableName, field.DBName))
-					case "blank":
-						conditions = append(conditions, fmt.Sprintf("%v.%v IS NULL", tableName, field.DBName))
-					default:
-						conditions = append(conditions, fmt.Sprintf("%v.%v = ?", tableName, field.DBName))
-					}
-				} else if filterfield.Operation == "In" {
+			appendNumber := func(field *schema.Field, parse func(string) (interface{}, error)) {
+				if filterfield.Operation == "In" {
 					conditions = append(conditions, fmt.Sprintf("%v.%v in (?)", tableName, field.DBName))
 					keywords = append(keywords, keywordEx)
-				}
-			}
-
-			appendFloat := func(field *schema.Field) {
-				if f, err := strconv.ParseFloat(keyword, 64); err == nil {
-					keywords = append(keywords, f)
-					switch filterfield.Operation {
-					case "gt":
-						conditions = append(conditions, fmt.Sprintf("%v.%v > ?", tableName, field.DBName))
-					case "lt":
-						conditions = append(conditions, fmt.Sprintf("%v.%v < ?", tableName, field.DBName))
-					default:
-						conditions = append(conditions, fmt.Sprintf("%v.%v = ?", tableName, field.DBName))
-					}
+				} else if condition, values, ok := numberCondition(fmt.Sprintf("%v.%v", tableName, field.DBName), filterfield.Operation, keyword, parse); ok {
+					conditions = append(conditions, condition)
+					keywords = append(keywords, values...)
 				}
 			}
 
@@ -454,9 +528,14 @@ func filterResourceByFields(res *Resource, filterFields []filterField, keyword s
 			}
 
 			appendTime := func(field *schema.Field) {
-				if parsedTime, err := utils.ParseTime(keyword, context); err == nil {
-					conditions = append(conditions, fmt.Sprintf("%v.%v = ?", tableName, field.DBName))
-					keywords = append(keywords, parsedTime)
+				if timeRange, err := ParseDatetimeRange(keyword, context); err == nil {
+					if timeRange.IsMoment() {
+						conditions = append(conditions, fmt.Sprintf("%v.%v = ?", tableName, field.DBName))
+						keywords = append(keywords, timeRange.Start)
+					} else {
+						conditions = append(conditions, fmt.Sprintf("(%v.%v >= ? AND %v.%v < ?)", tableName, field.DBName, tableName, field.DBName))
+						keywords = append(keywords, timeRange.Start, timeRange.End)
+					}
 				}
 			}
 
@@ -465,10 +544,6 @@ func filterResourceByFields(res *Resource, filterFields []filterField, keyword s
 				case time.Time, *time.Time:
 					appendTime(field)
 					// add support for sql null fields
-				case sql.NullInt64:
-					appendInteger(field)
-				case sql.NullFloat64:
-					appendFloat(field)
 				case sql.NullString:
 					appendString(field)
 				case sql.NullBool:
@@ -480,14 +555,12 @@ func filterResourceByFields(res *Resource, filterFields []filterField, keyword s
 
 			if field := currentScope.LookUpField(column); field != nil {
 
-				if rvfield := rfValue.FieldByName(column); rvfield.FieldByName(field.Name).Kind() != reflect.Struct {
+				if parse := numberParser(field.FieldType); parse != nil {
+					appendNumber(field, parse)
+				} else if rvfield := rfValue.FieldByName(column); rvfield.FieldByName(field.Name).Kind() != reflect.Struct {
 					switch rvfield.FieldByName(field.Name).Kind() {
 					case reflect.String:
 						appendString(field)
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						appendInteger(field)
-					case reflect.Float32, reflect.Float64:
-						appendFloat(field)
 					case reflect.Bool:
 						appendBool(field)
 					case reflect.Struct, reflect.Ptr:
@@ -6,9 +6,10 @@ import (
 
 	"github.com/simonedbarber/qor"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// SettingsStorageInterface settings storage interface
+// SettingsStorageInterface settings storage interface, settings of IndexSettingsKey, FavouritesKey, PrivateSavedFiltersKey are saved for the user, others are shared by all users
 type SettingsStorageInterface interface {
 	Get(key string, value interface{}, context *Context) error
 	Save(key string, value interface{}, res *Resource, user qor.CurrentUser, context *Context) error
@@ -30,6 +31,9 @@ type QorAdminSetting struct {
 	Value    string `gorm:"size:65532"`
 }
 
+// perUserSettingsKeys keys of settings saved for each user, other settings are shared by all users
+var perUserSettingsKeys = map[string]bool{IndexSettingsKey: true, FavouritesKey: true, PrivateSavedFiltersKey: true}
+
 type settings struct{}
 
 // Get load admin settings
@@ -46,12 +50,16 @@ func (settings) Get(key string, value interface{}, context *Context) error {
 		resParams = context.Resource.ToParam()
 	}
 
-	if context.CurrentUser != nil {
-		userID = ""
+	if !perUserSettingsKeys[key] {
+		tx.Where(sqlCondition, key, resParams, "", userID, "").Order("user_id DESC, resource DESC, id DESC").Find(&settings)
+	} else {
+		// user's settings of the resource override defaults
+		if context.CurrentUser != nil {
+			userID = fmt.Sprint(context.CurrentUser.GetID())
+		}
+		tx.Where(sqlCondition, key, resParams, "", userID, "").Order("user_id ASC, resource ASC, id ASC").Find(&settings)
 	}
 
-	tx.Where(sqlCondition, key, resParams, "", userID, "").Order("user_id DESC, resource DESC, id DESC").Find(&settings)
-
 	for _, setting := range settings {
 		if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
 			return err
@@ -78,8 +86,8 @@ func (settings) Save(key string, value interface{}, res *Resource, user qor.Curr
 		resParams = res.ToParam()
 	}
 
-	if user != nil {
-		userID = ""
+	if user != nil && perUserSettingsKeys[key] {
+		userID = fmt.Sprint(user.GetID())
 	}
 
 	err = tx.Where(QorAdminSetting{
@@ -90,3 +98,22 @@ func (settings) Save(key string, value interface{}, res *Resource, user qor.Curr
 
 	return err
 }
+
+// lock lock the settings row shared by all users in context's transaction, the row is created if it doesn't exist, so it could be locked
+func (settings) lock(key string, res *Resource, context *Context) error {
+	var (
+		tx        = context.GetDB()
+		resParams = ""
+		rows      []QorAdminSetting
+	)
+
+	if res != nil {
+		resParams = res.ToParam()
+	}
+
+	sqlCondition := fmt.Sprintf("%v = ? AND resource = ? AND user_id = ?", "key")
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(sqlCondition, key, resParams, "").Find(&rows).Error; err != nil || len(rows) > 0 {
+		return err
+	}
+	return tx.Create(&QorAdminSetting{Key: key, Resource: resParams, Value: "null"}).Error
+}
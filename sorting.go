@@ -0,0 +1,232 @@
+package admin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/simonedbarber/qor/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// orderByKeyRegexp valid sort key, e.g. `name`, `created_at`, `Customer.Name`
+var orderByKeyRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// SortKey a parsed sort key of order_by param
+type SortKey struct {
+	Name string
+	Desc bool
+}
+
+// String format sort key back to order_by param
+func (key SortKey) String() string {
+	if key.Desc {
+		return "-" + key.Name
+	}
+	return key.Name
+}
+
+// ParseOrderBy parse order_by param to sort keys, sort keys are separated by comma, prefix `-` or suffix `_desc` means descending
+//
+//	ParseOrderBy("status,-created_at") => [{status false} {created_at true}]
+//	ParseOrderBy("name_desc")          => [{name true}]
+func ParseOrderBy(orderBy string) (keys []SortKey) {
+	for _, name := range strings.Split(orderBy, ",") {
+		var key SortKey
+		name = strings.TrimSpace(name)
+
+		if strings.HasPrefix(name, "-") {
+			key.Desc = true
+			name = strings.TrimPrefix(name, "-")
+		} else if strings.HasSuffix(name, "_desc") {
+			key.Desc = true
+			name = strings.TrimSuffix(name, "_desc")
+		}
+
+		if orderByKeyRegexp.MatchString(name) {
+			key.Name = name
+			keys = append(keys, key)
+		}
+	}
+	return
+}
+
+// sortableColumn check if attr of SortableAttrs could be used to sort, it could be a column, a meta with SortExpression, or a belongs_to relation's column like `Customer.Name`
+func (res *Resource) sortableColumn(attr string) bool {
+	if strings.Contains(attr, ".") {
+		_, _, ok := res.sortRelation(attr)
+		return ok
+	}
+
+	for _, meta := range res.metas {
+		if meta.Name == attr && meta.SortExpression != "" {
+			return true
+		}
+	}
+
+	if field := utils.NewScope(res.Value).LookUpField(attr); field != nil && field.DBName != "" {
+		return true
+	}
+	return false
+}
+
+// sortRelation find belongs_to relationship and its field for column like `Customer.Name`
+func (res *Resource) sortRelation(column string) (*schema.Relationship, *schema.Field, bool) {
+	names := strings.SplitN(column, ".", 2)
+	if len(names) != 2 {
+		return nil, nil, false
+	}
+
+	scope := utils.NewScope(res.Value)
+	if relationship := scope.Relationships.Relations[names[0]]; relationship != nil && relationship.Type == schema.BelongsTo && relationship.FieldSchema != nil {
+		if field := relationship.FieldSchema.LookUpField(names[1]); field != nil && field.DBName != "" {
+			return relationship, field, true
+		}
+	}
+	return nil, nil, false
+}
+
+// sortAttrOf find sortable attr for sort key, sort key could be attr's name or its db name
+func (res *Resource) sortAttrOf(key SortKey) (string, bool) {
+	for _, attr := range res.SortableAttrs() {
+		if strings.EqualFold(attr, key.Name) {
+			return attr, true
+		}
+
+		if !strings.Contains(attr, ".") {
+			if meta := res.GetMeta(attr); meta != nil && meta.DBName() != "" && meta.DBName() == key.Name {
+				return attr, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isSortableOrder check all keys of order_by are allowed with SortableAttrs
+func (res *Resource) isSortableOrder(orderBy string) bool {
+	keys := ParseOrderBy(orderBy)
+	for _, key := range keys {
+		if _, ok := res.sortAttrOf(key); !ok {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+// applyOrderBy sort db with order_by param, keys not allowed by SortableAttrs will be ignored
+func (res *Resource) applyOrderBy(db *gorm.DB, orderBy string) *gorm.DB {
+	scope := utils.NewScope(res.Value)
+
+	for _, key := range ParseOrderBy(orderBy) {
+		attr, ok := res.sortAttrOf(key)
+		if !ok {
+			continue
+		}
+
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+
+		if relationship, field, ok := res.sortRelation(attr); ok {
+			// sort with a correlated subquery of the belongs_to table instead of LEFT JOIN, as conditions of scopes, filters and handlers are often
+			// unqualified columns like `state = ?`, joining the table would make them ambiguous if the table has same columns, and joined columns
+			// won't be mixed into selected columns and total count, the subquery looks up the table by its primary key, so it is an index lookup per record
+			var (
+				alias      = "sort_" + utils.ToParamString(relationship.Name)
+				conditions []string
+			)
+
+			for _, reference := range relationship.References {
+				if reference.PrimaryKey != nil && reference.ForeignKey != nil {
+					conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", alias, reference.PrimaryKey.DBName, scope.Table, reference.ForeignKey.DBName))
+				}
+			}
+
+			if len(conditions) > 0 {
+				db = db.Order(fmt.Sprintf("(SELECT %v.%v FROM %v %v WHERE %v) %v", alias, field.DBName, relationship.FieldSchema.Table, alias, strings.Join(conditions, " AND "), direction))
+			}
+			continue
+		}
+
+		if meta := res.GetMeta(attr); meta != nil {
+			if meta.SortExpression != "" {
+				db = db.Order(fmt.Sprintf("%v %v", meta.SortExpression, direction))
+			} else if dbName := meta.DBName(); dbName != "" {
+				db = db.Order(fmt.Sprintf("%v.%v %v", scope.Table, dbName, direction))
+			}
+		}
+	}
+
+	return db
+}
+
+// sortKeyOfMeta return key used in order_by param to sort with meta, blank if meta is not sortable
+//
+// a belongs_to meta like `Customer` is sortable with `Customer.Name` if it is one of SortableAttrs
+func (res *Resource) sortKeyOfMeta(meta *Meta) string {
+	for _, attr := range res.SortableAttrs() {
+		if attr == meta.Name {
+			if meta.SortExpression != "" {
+				return meta.Name
+			}
+			if dbName := meta.DBName(); dbName != "" {
+				return dbName
+			}
+		}
+	}
+
+	for _, attr := range res.SortableAttrs() {
+		if strings.HasPrefix(attr, meta.Name+".") {
+			return attr
+		}
+	}
+	return ""
+}
+
+// SortOrder sort status of an index column
+type SortOrder struct {
+	Key    string
+	Sorted bool
+	Desc   bool
+	// OrderBy order_by param used to sort only with the column, toggle direction if already sorted
+	OrderBy string
+	// AppendOrderBy order_by param used to add the column to current sort keys, toggle direction if already sorted
+	AppendOrderBy string
+}
+
+// metaSortOrder return sort status of meta for current order_by, nil if meta is not sortable
+func (context *Context) metaSortOrder(meta *Meta) *SortOrder {
+	key := context.Resource.sortKeyOfMeta(meta)
+	if key == "" {
+		return nil
+	}
+
+	orderBy := context.Request.URL.Query().Get("order_by")
+	if orderBy == "" {
+		orderBy = context.getIndexSettings().OrderBy
+	}
+
+	var (
+		sortOrder = &SortOrder{Key: key}
+		keys      []string
+	)
+
+	for _, sortKey := range ParseOrderBy(orderBy) {
+		if sortKey.Name == key {
+			sortOrder.Sorted, sortOrder.Desc = true, sortKey.Desc
+			sortKey.Desc = !sortKey.Desc
+		}
+		keys = append(keys, sortKey.String())
+	}
+
+	if sortOrder.Sorted {
+		sortOrder.OrderBy = SortKey{Name: key, Desc: !sortOrder.Desc}.String()
+	} else {
+		sortOrder.OrderBy = key
+		keys = append(keys, key)
+	}
+	sortOrder.AppendOrderBy = strings.Join(keys, ",")
+	return sortOrder
+}
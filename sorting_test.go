@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	cases := map[string][]SortKey{
+		"name":                     {{Name: "name"}},
+		"name_desc":                {{Name: "name", Desc: true}},
+		"status,-created_at":       {{Name: "status"}, {Name: "created_at", Desc: true}},
+		" status , -Customer.Name": {{Name: "status"}, {Name: "Customer.Name", Desc: true}},
+		"name;drop table,-age":     {{Name: "age", Desc: true}},
+		"":                         nil,
+	}
+
+	for orderBy, expected := range cases {
+		if keys := ParseOrderBy(orderBy); !reflect.DeepEqual(keys, expected) {
+			t.Errorf("order by %q should be parsed as %v, but got %v", orderBy, expected, keys)
+		}
+	}
+
+	if str := (SortKey{Name: "created_at", Desc: true}).String(); str != "-created_at" {
+		t.Errorf("sort key should be formatted as -created_at, but got %v", str)
+	}
+}
@@ -25,7 +25,14 @@ func (JSONTransformer) Encode(writer io.Writer, encoder Encoder) error {
 		res     = encoder.Resource
 	)
 
-	js, err := json.MarshalIndent(convertObjectToJSONMap(res, context, encoder.Result, encoder.Action), "", "\t")
+	result := convertObjectToJSONMap(res, context, encoder.Result, encoder.Action)
+	if encoder.Action == "index" && context != nil {
+		if pagination, ok := context.paginationBody(); ok {
+			result = map[string]interface{}{"Records": result, "Pagination": pagination}
+		}
+	}
+
+	js, err := json.MarshalIndent(result, "", "\t")
 	if err != nil {
 		result := make(map[string]string)
 		result["error"] = err.Error()
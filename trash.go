@@ -0,0 +1,269 @@
+package admin
+
+import (
+	stdcontext "context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TrashScopeName name of the scope lists soft deleted records
+const TrashScopeName = "Trash"
+
+// TrashConfig trash configuration of a resource, refer Resource.UseTrash
+type TrashConfig struct {
+	// RestorePermission, PurgePermission permissions of Restore, Purge actions, they are checked like other actions', in addition to Delete permission of the resource
+	RestorePermission *roles.Permission
+	PurgePermission   *roles.Permission
+	// RetentionDays purge soft deleted records after days with Admin.RunTrashRetention, 0 means keep forever
+	RetentionDays int
+}
+
+// UseTrash add a trash view for resource of soft deleted model, which is a scope lists deleted records only, and bulk actions Restore, Purge, e.g:
+//
+//	product.UseTrash(&admin.TrashConfig{
+//		PurgePermission: roles.Allow(roles.Update, "admin"),
+//		RetentionDays:   30,
+//	})
+func (res *Resource) UseTrash(config *TrashConfig) {
+	if config == nil {
+		config = &TrashConfig{}
+	}
+
+	if _, err := res.deletedAtField(); err != nil {
+		utils.ExitWithMsg(err)
+	}
+	res.trash = config
+
+	res.Scope(&Scope{
+		Name:  TrashScopeName,
+		Label: "Trash",
+		Visible: func(context *Context) bool {
+			return res.HasPermission(roles.Delete, context.Context)
+		},
+		Handler: func(db *gorm.DB, context *qor.Context) *gorm.DB {
+			// the scope could be requested with params even it is invisible
+			if !res.HasPermission(roles.Delete, context) {
+				db.AddError(roles.ErrPermissionDenied)
+				return db
+			}
+
+			field, _ := res.deletedAtField()
+			return db.Unscoped().Where(fmt.Sprintf("%v.%v IS NOT NULL", utils.NewScope(res.Value).Table, field.DBName))
+		},
+	})
+
+	res.Action(&Action{
+		Name:       "Restore",
+		Permission: config.RestorePermission,
+		Modes:      []string{"batch"},
+		Visible: func(record interface{}, context *Context) bool {
+			return isTrashRequest(context) && res.HasPermission(roles.Delete, context.Context)
+		},
+		Handler: func(argument *ActionArgument) error {
+			if !res.HasPermission(roles.Delete, argument.Context.Context) {
+				return roles.ErrPermissionDenied
+			}
+			return res.restoreTrash(argument)
+		},
+	})
+
+	res.Action(&Action{
+		Name:       "Purge",
+		Permission: config.PurgePermission,
+		Modes:      []string{"batch"},
+		Visible: func(record interface{}, context *Context) bool {
+			return isTrashRequest(context) && res.HasPermission(roles.Delete, context.Context)
+		},
+		Handler: func(argument *ActionArgument) error {
+			if !res.HasPermission(roles.Delete, argument.Context.Context) {
+				return roles.ErrPermissionDenied
+			}
+			return res.purgeTrash(argument)
+		},
+	})
+}
+
+// isTrashRequest check if current request is listing trash
+func isTrashRequest(context *Context) bool {
+	if context.Request == nil {
+		return false
+	}
+
+	for _, scope := range context.Request.URL.Query()["scopes"] {
+		if scope == TrashScopeName {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrashAction check if the action is trash's action Restore or Purge
+func (res *Resource) isTrashAction(action *Action) bool {
+	return res.trash != nil && (action.Name == "Restore" || action.Name == "Purge")
+}
+
+// deletedAtField return soft delete field of resource's model
+func (res *Resource) deletedAtField() (*schema.Field, error) {
+	scope := utils.NewScope(res.Value)
+	for _, field := range scope.Fields {
+		if field.FieldType == reflect.TypeOf(gorm.DeletedAt{}) && field.DBName != "" {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("resource %v's model doesn't support soft delete, trash requires a gorm.DeletedAt field", res.Name)
+}
+
+// findTrashedRecords find selected soft deleted records of action
+func (res *Resource) findTrashedRecords(argument *ActionArgument) (interface{}, error) {
+	var (
+		context   = argument.Context
+		results   = res.NewSlice()
+		sqls      []string
+		sqlParams []interface{}
+	)
+
+	if len(argument.PrimaryValues) == 0 {
+		return results, nil
+	}
+
+	for _, primaryValue := range argument.PrimaryValues {
+		primaryQuerySQL, primaryParams := res.ToPrimaryQueryParams(primaryValue, context.Context)
+		sqls = append(sqls, primaryQuerySQL)
+		sqlParams = append(sqlParams, primaryParams...)
+	}
+
+	field, err := res.deletedAtField()
+	if err != nil {
+		return results, err
+	}
+
+	err = context.GetDB().Unscoped().
+		Where(strings.Join(sqls, " OR "), sqlParams...).
+		Where(fmt.Sprintf("%v.%v IS NOT NULL", utils.NewScope(res.Value).Table, field.DBName)).
+		Find(results).Error
+	return results, err
+}
+
+// restoreTrash restore selected soft deleted records
+func (res *Resource) restoreTrash(argument *ActionArgument) error {
+	field, err := res.deletedAtField()
+	if err != nil {
+		return err
+	}
+
+	results, err := res.findTrashedRecords(argument)
+	if err != nil {
+		return err
+	}
+
+	records := reflect.Indirect(reflect.ValueOf(results))
+	return argument.Context.GetDB().Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < records.Len(); i++ {
+			record := records.Index(i).Addr().Interface()
+			if err := tx.Unscoped().Model(record).Update(field.DBName, nil).Error; err != nil {
+				return err
+			}
+
+			if res.searchBackend != nil {
+				context := argument.Context.Context.Clone()
+				context.SetDB(tx)
+				if err := res.searchBackend.Index(record, res, context); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// purgeTrash delete selected soft deleted records permanently
+func (res *Resource) purgeTrash(argument *ActionArgument) error {
+	results, err := res.findTrashedRecords(argument)
+	if err != nil {
+		return err
+	}
+
+	return argument.Context.GetDB().Transaction(func(tx *gorm.DB) error {
+		ctx := argument.Context.clone()
+		ctx.Context = argument.Context.Context.Clone()
+		ctx.SetDB(tx)
+		return res.removeRecords(trashedRecords(results), nil, ctx, true, nil)
+	})
+}
+
+// PurgeExpiredTrash delete records soft deleted before the resource's retention days permanently, dependent records are handled like purging with the Purge action, return count of purged records
+func (res *Resource) PurgeExpiredTrash(db *gorm.DB) (int64, error) {
+	if res.trash == nil || res.trash.RetentionDays <= 0 {
+		return 0, nil
+	}
+
+	field, err := res.deletedAtField()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		count     int64
+		expiredAt = time.Now().AddDate(0, 0, -res.trash.RetentionDays)
+	)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		results := res.NewSlice()
+		if err := tx.Unscoped().Where(fmt.Sprintf("%v < ?", field.DBName), expiredAt).Find(results).Error; err != nil {
+			return err
+		}
+
+		records := trashedRecords(results)
+		count = int64(len(records))
+		context := &Context{Context: &qor.Context{Config: &qor.Config{DB: tx}}, Admin: res.GetAdmin(), Resource: res, Settings: map[string]interface{}{}}
+		return res.removeRecords(records, nil, context, true, nil)
+	})
+	return count, err
+}
+
+// trashedRecords return records of slice results
+func trashedRecords(results interface{}) (records []interface{}) {
+	values := reflect.Indirect(reflect.ValueOf(results))
+	for i := 0; i < values.Len(); i++ {
+		record := values.Index(i)
+		if record.Kind() != reflect.Ptr {
+			record = record.Addr()
+		}
+		records = append(records, record.Interface())
+	}
+	return records
+}
+
+// RunTrashRetention purge expired trash of resources with RetentionDays periodically until ctx is done, errors are reported with onError if it is not nil, e.g:
+//
+//	go Admin.RunTrashRetention(ctx, time.Hour, func(res *admin.Resource, err error) { log.Println(err) })
+func (admin *Admin) RunTrashRetention(ctx stdcontext.Context, interval time.Duration, onError func(res *Resource, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, res := range admin.GetResources() {
+			if res.trash == nil || res.trash.RetentionDays <= 0 {
+				continue
+			}
+
+			if _, err := res.PurgeExpiredTrash(admin.DB.WithContext(ctx)); err != nil && onError != nil {
+				onError(res, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/simonedbarber/qor"
+)
+
+func TestIsTrashRequest(t *testing.T) {
+	for url, expected := range map[string]bool{
+		"/admin/products":                              false,
+		"/admin/products?scopes=Trash":                 true,
+		"/admin/products?scopes=Enabled&scopes=Trash":  true,
+		"/admin/products?scopes=Enabled&keyword=Trash": false,
+	} {
+		context := &Context{Context: &qor.Context{Request: httptest.NewRequest("GET", url, nil)}}
+		if isTrashRequest(context) != expected {
+			t.Errorf("%v should be trash request: %v", url, expected)
+		}
+	}
+}
@@ -94,6 +94,18 @@ $(function() {
       }
     }
 
+    // batch actions open url with selected records, e.g: merge
+    if (openData.withSelection) {
+      if (!actionData) {
+        window.QOR.qorConfirm(openData.errorNoItem);
+        return false;
+      }
+
+      openData.url +=
+        (openData.url.indexOf("?") > -1 ? "&" : "?") +
+        $.param({ "primary_values[]": actionData });
+    }
+
     openData.$target = $target;
 
     if (!openData.method || openData.method.toUpperCase() == "GET") {
@@ -218,6 +218,25 @@
                 return;
             }
 
+            // preview dependent records before deleting
+            if (properties.confirmUrl && !properties.confirmPreviewed) {
+                $.getJSON(properties.confirmUrl, $.param(ajaxForm.formData || [])).done(function(data) {
+                    if (data.Blocked) {
+                        QOR.qorConfirm(data.Message);
+                        return;
+                    }
+
+                    _this.ajaxForm.properties = $.extend({}, properties, {
+                        confirm: data.Message ? properties.confirm + "\n" + data.Message : properties.confirm,
+                        confirmPreviewed: true
+                    });
+                    _this.submit($actionButton);
+                }).fail(function(err) {
+                    QOR.handleAjaxError(err);
+                });
+                return;
+            }
+
             if (properties.confirm) {
                 QOR.qorConfirm(properties, function(confirm) {
                     if (confirm) {
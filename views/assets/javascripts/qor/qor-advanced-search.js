@@ -140,16 +140,18 @@
     },
 
     saveFilter: function() {
-      let name = this.$modal.find("#qor-advanced-filter__savename").val();
+      let name = this.$modal.find("#qor-advanced-filter__savename").val(),
+        visibility = this.$modal.find("#qor-advanced-filter__savevisibility").val(),
+        isDefault = this.$modal.find("#qor-advanced-filter__savedefault").is(":checked");
 
       if (!name) {
         return;
       }
 
       this.$form
-        .prepend(
-          `<input type="hidden" name="filter_saving_name" value="${name}"  />`
-        )
+        .prepend($('<input type="hidden" name="filter_saving_name" />').val(name))
+        .prepend($('<input type="hidden" name="filter_saving_visibility" />').val(visibility))
+        .prepend($('<input type="hidden" name="filter_saving_default" />').val(isDefault ? "true" : ""))
         .submit();
     },
 
@@ -221,6 +223,20 @@
                         <label class="mdl-textfield__label" for="qor-advanced-filter__savename">Please enter name for this filter</label>
                     </div>
 
+                    <div class="qor-field">
+                        <label class="qor-field__label" for="qor-advanced-filter__savevisibility">Share with</label>
+                        <select class="qor-field__input" id="qor-advanced-filter__savevisibility">
+                            <option value="private">Only me</option>
+                            <option value="role">My roles</option>
+                            <option value="global">Everyone</option>
+                        </select>
+                    </div>
+
+                    <label class="mdl-checkbox mdl-js-checkbox" for="qor-advanced-filter__savedefault">
+                        <input type="checkbox" id="qor-advanced-filter__savedefault" class="mdl-checkbox__input">
+                        <span class="mdl-checkbox__label">Open by default</span>
+                    </label>
+
                 </div>
                 <div class="mdl-card__actions">
                     <a class="mdl-button mdl-button--colored mdl-button--raised qor-advanced-filter__savefilter">Save This Filter</a>
@@ -0,0 +1,105 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.bulkedit",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CHANGE = "change." + NAMESPACE,
+    CLASS_FIELD = ".qor-bulk-edit__field",
+    CLASS_CHANGE = ".qor-bulk-edit__change input";
+
+  function QorBulkEdit(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorBulkEdit.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorBulkEdit.prototype = {
+    constructor: QorBulkEdit,
+
+    init: function() {
+      this.bind();
+    },
+
+    bind: function() {
+      this.$element.on(EVENT_CHANGE, CLASS_FIELD + " :input", this.change.bind(this));
+    },
+
+    unbind: function() {
+      this.$element.off(EVENT_CHANGE);
+    },
+
+    // editing a field checks its "change this" checkbox
+    change: function(e) {
+      let $input = $(e.target),
+        $field = $input.closest(CLASS_FIELD),
+        $checkbox = $field.find(CLASS_CHANGE);
+
+      if ($input.is($checkbox)) {
+        $field.toggleClass("is-changed", $checkbox.prop("checked"));
+        return;
+      }
+
+      $checkbox.prop("checked", true);
+      $field.addClass("is-changed");
+    },
+
+    destroy: function() {
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorBulkEdit.DEFAULTS = {};
+
+  QorBulkEdit.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorBulkEdit(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.bulkedit"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorBulkEdit.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorBulkEdit.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorBulkEdit;
+});
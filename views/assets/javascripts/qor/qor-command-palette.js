@@ -0,0 +1,256 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.commandpalette",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_KEYDOWN = "keydown." + NAMESPACE,
+    EVENT_INPUT = "input." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    EVENT_MOUSEMOVE = "mousemove." + NAMESPACE,
+    CLASS_IS_SHOWN = "is-shown",
+    CLASS_IS_SELECTED = "is-selected",
+    CLASS_ENTRY = ".qor-command-palette__entry";
+
+  function QorCommandPalette(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorCommandPalette.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorCommandPalette.prototype = {
+    constructor: QorCommandPalette,
+
+    init: function() {
+      this.$input = this.$element.find(".qor-command-palette__input");
+      this.$list = this.$element.find(".qor-command-palette__list");
+      this.entries = [];
+      this.selected = 0;
+      this.bind();
+    },
+
+    bind: function() {
+      $(document).on(EVENT_KEYDOWN, this.toggle.bind(this));
+      this.$input
+        .on(EVENT_INPUT, this.search.bind(this))
+        .on(EVENT_KEYDOWN, this.navigate.bind(this));
+      this.$list
+        .on(EVENT_CLICK, CLASS_ENTRY, this.clickEntry.bind(this))
+        .on(EVENT_MOUSEMOVE, CLASS_ENTRY, this.hoverEntry.bind(this));
+      this.$element.on(EVENT_CLICK, this.clickOutside.bind(this));
+    },
+
+    unbind: function() {
+      $(document).off(EVENT_KEYDOWN);
+      this.$input.off(EVENT_INPUT).off(EVENT_KEYDOWN);
+      this.$list.off(EVENT_CLICK).off(EVENT_MOUSEMOVE);
+      this.$element.off(EVENT_CLICK);
+    },
+
+    // Ctrl-K or Cmd-K toggles the palette, Escape closes it
+    toggle: function(e) {
+      if ((e.ctrlKey || e.metaKey) && (e.key === "k" || e.key === "K")) {
+        e.preventDefault();
+        this.$element.hasClass(CLASS_IS_SHOWN) ? this.hide() : this.show();
+      } else if (e.key === "Escape" && this.$element.hasClass(CLASS_IS_SHOWN)) {
+        this.hide();
+      }
+    },
+
+    show: function() {
+      this.$element.addClass(CLASS_IS_SHOWN);
+      this.$input.val("").trigger("focus");
+      this.search();
+    },
+
+    hide: function() {
+      this.$element.removeClass(CLASS_IS_SHOWN);
+      clearTimeout(this.timer);
+    },
+
+    clickOutside: function(e) {
+      if (e.target === this.$element[0]) {
+        this.hide();
+      }
+    },
+
+    search: function() {
+      let keyword = this.$input.val();
+
+      clearTimeout(this.timer);
+      this.timer = setTimeout(
+        function() {
+          if (this.xhr) {
+            this.xhr.abort();
+          }
+
+          this.xhr = $.getJSON(this.$element.data("url"), {
+            keyword: keyword,
+            resource: this.$element.data("resource")
+          }).done(
+            function(entries) {
+              this.render(entries || []);
+            }.bind(this)
+          );
+        }.bind(this),
+        this.options.delay
+      );
+    },
+
+    render: function(entries) {
+      this.entries = entries;
+      this.selected = 0;
+      this.$list.empty();
+
+      if (!entries.length) {
+        this.$list.append($('<li class="qor-command-palette__empty">').text(this.$element.data("emptyLabel")));
+        return;
+      }
+
+      entries.forEach(
+        function(entry, index) {
+          let $entry = $('<li class="qor-command-palette__entry">')
+            .attr("data-index", index)
+            .addClass("qor-command-palette__entry--" + entry.Type);
+
+          $('<span class="qor-command-palette__label">').text(entry.Label).appendTo($entry);
+          if (entry.Group) {
+            $('<span class="qor-command-palette__group">').text(entry.Group).appendTo($entry);
+          }
+          this.$list.append($entry);
+        }.bind(this)
+      );
+
+      this.select(0);
+    },
+
+    select: function(index) {
+      let $entries = this.$list.find(CLASS_ENTRY);
+
+      if (!$entries.length) {
+        return;
+      }
+
+      this.selected = (index + $entries.length) % $entries.length;
+      $entries.removeClass(CLASS_IS_SELECTED);
+      $entries
+        .eq(this.selected)
+        .addClass(CLASS_IS_SELECTED)[0]
+        .scrollIntoView({block: "nearest"});
+    },
+
+    navigate: function(e) {
+      switch (e.key) {
+        case "ArrowDown":
+          e.preventDefault();
+          this.select(this.selected + 1);
+          break;
+        case "ArrowUp":
+          e.preventDefault();
+          this.select(this.selected - 1);
+          break;
+        case "Enter":
+          e.preventDefault();
+          this.open(this.entries[this.selected], e.ctrlKey || e.metaKey);
+          break;
+      }
+    },
+
+    clickEntry: function(e) {
+      let index = $(e.currentTarget).data("index");
+      this.open(this.entries[index], e.ctrlKey || e.metaKey);
+    },
+
+    hoverEntry: function(e) {
+      let index = $(e.currentTarget).data("index");
+      if (index !== this.selected) {
+        this.select(index);
+      }
+    },
+
+    // open entry's url, actions with other methods are requested then reload current page
+    open: function(entry, newWindow) {
+      if (!entry) {
+        return;
+      }
+
+      if (entry.Method && entry.Method !== "GET") {
+        if (!window.confirm(this.$element.data("confirmLabel"))) {
+          return;
+        }
+
+        $.ajax(entry.URL, {method: entry.Method, dataType: "json"}).always(function() {
+          window.location.reload();
+        });
+        return;
+      }
+
+      if (newWindow || entry.OpenType === "_blank") {
+        window.open(entry.URL, "_blank");
+      } else {
+        window.location.href = entry.URL;
+      }
+      this.hide();
+    },
+
+    destroy: function() {
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorCommandPalette.DEFAULTS = {
+    delay: 150
+  };
+
+  QorCommandPalette.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorCommandPalette(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.commandpalette"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorCommandPalette.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorCommandPalette.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorCommandPalette;
+});
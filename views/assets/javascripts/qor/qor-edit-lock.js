@@ -0,0 +1,142 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.editlock",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    EVENT_BEFOREUNLOAD = "beforeunload." + NAMESPACE,
+    CLASS_LOCK = ".qor-edit-lock",
+    CLASS_MESSAGE = ".qor-edit-lock__message",
+    CLASS_TAKE_OVER = ".qor-edit-lock__take-over";
+
+  function QorEditLock(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorEditLock.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorEditLock.prototype = {
+    constructor: QorEditLock,
+
+    init: function() {
+      let interval = this.$element.data("interval") || this.options.interval;
+
+      this.url = this.$element.data("url");
+      this.timer = window.setInterval(this.heartbeat.bind(this), interval);
+      this.bind();
+    },
+
+    bind: function() {
+      this.$element.on(EVENT_CLICK, CLASS_TAKE_OVER, this.takeOver.bind(this));
+      $(window).on(EVENT_BEFOREUNLOAD, this.release.bind(this));
+    },
+
+    unbind: function() {
+      this.$element.off(EVENT_CLICK);
+      $(window).off(EVENT_BEFOREUNLOAD);
+    },
+
+    // renew the lock while edit page is open
+    heartbeat: function(data) {
+      $.ajax(this.url, {
+        method: "POST",
+        dataType: "json",
+        data: data || {},
+        success: this.render.bind(this),
+        error: function(err) {
+          if (data) {
+            QOR.handleAjaxError(err);
+          }
+        }
+      });
+    },
+
+    takeOver: function() {
+      this.heartbeat({ take_over: true });
+    },
+
+    release: function() {
+      if (navigator.sendBeacon) {
+        navigator.sendBeacon(this.url + "?release=true");
+      } else {
+        $.ajax(this.url, { method: "DELETE", async: false });
+      }
+    },
+
+    render: function(data) {
+      let $lock = this.$element.find(CLASS_LOCK);
+
+      if (data.Locked) {
+        $lock.hide();
+        return;
+      }
+
+      $lock.find(CLASS_MESSAGE).text(data.Message);
+      $lock.find(CLASS_TAKE_OVER).toggle(!!data.CanTakeOver);
+      $lock.show();
+    },
+
+    destroy: function() {
+      window.clearInterval(this.timer);
+      this.release();
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorEditLock.DEFAULTS = {
+    interval: 40000
+  };
+
+  QorEditLock.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorEditLock(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.editlock"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorEditLock.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorEditLock.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorEditLock;
+});
@@ -0,0 +1,158 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.favourites",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    EVENT_DRAGSTART = "dragstart." + NAMESPACE,
+    EVENT_DRAGOVER = "dragover." + NAMESPACE,
+    EVENT_DROP = "drop." + NAMESPACE,
+    CLASS_PIN = ".qor-favourite-pin",
+    CLASS_FAVOURITE = ".qor-menu__favourites > .qor-menu > li";
+
+  function QorFavourites(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorFavourites.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorFavourites.prototype = {
+    constructor: QorFavourites,
+
+    init: function() {
+      this.$element.find(CLASS_FAVOURITE).attr("draggable", true);
+      this.bind();
+    },
+
+    bind: function() {
+      // saved filters could be pinned outside of sidebar
+      $(document).on(EVENT_CLICK, CLASS_PIN, this.pin.bind(this));
+      this.$element
+        .on(EVENT_DRAGSTART, CLASS_FAVOURITE, this.dragstart.bind(this))
+        .on(EVENT_DRAGOVER, CLASS_FAVOURITE, this.dragover)
+        .on(EVENT_DROP, CLASS_FAVOURITE, this.drop.bind(this));
+    },
+
+    unbind: function() {
+      $(document).off(EVENT_CLICK, CLASS_PIN);
+      this.$element
+        .off(EVENT_DRAGSTART)
+        .off(EVENT_DRAGOVER)
+        .off(EVENT_DROP);
+    },
+
+    pin: function(e) {
+      let $pin = $(e.currentTarget),
+        data = {};
+
+      e.preventDefault();
+      e.stopPropagation();
+      data[$pin.hasClass("is-pinned") ? "remove" : "add"] = $pin.data("favouriteKey");
+      this.submit(data);
+    },
+
+    dragstart: function(e) {
+      this.$dragging = $(e.target).closest("li");
+    },
+
+    dragover: function(e) {
+      e.preventDefault();
+    },
+
+    drop: function(e) {
+      let $target = $(e.target).closest("li");
+
+      e.preventDefault();
+      if (!this.$dragging || $target.is(this.$dragging) || !$target.parent().is(this.$dragging.parent())) {
+        return;
+      }
+
+      if (this.$dragging.index() < $target.index()) {
+        $target.after(this.$dragging);
+      } else {
+        $target.before(this.$dragging);
+      }
+      this.$dragging = null;
+
+      this.submit({
+        "order[]": $target
+          .parent()
+          .children("li")
+          .map(function() {
+            return $(this).data("favouriteKey");
+          })
+          .get()
+      });
+    },
+
+    submit: function(data) {
+      $.ajax(this.$element.data("url"), {
+        method: "POST",
+        data: data,
+        traditional: true,
+        dataType: "json"
+      }).always(function() {
+        window.location.reload();
+      });
+    },
+
+    destroy: function() {
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorFavourites.DEFAULTS = {};
+
+  QorFavourites.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorFavourites(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.favourites"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorFavourites.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorFavourites.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorFavourites;
+});
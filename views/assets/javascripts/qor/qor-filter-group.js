@@ -0,0 +1,282 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.filtergroup",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    EVENT_CHANGE = "change." + NAMESPACE,
+    EVENT_SUBMIT = "submit." + NAMESPACE,
+    CLASS_GROUP = ".qor-filter-group__group",
+    CLASS_CONDITION = ".qor-filter-group__condition",
+    STRING_OPERATIONS = ["conts", "eq", "start_with", "end_with"],
+    NUMBER_OPERATIONS = ["eq", "ne", "gt", "gteq", "lt", "lteq"];
+
+  function QorFilterGroup(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorFilterGroup.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorFilterGroup.prototype = {
+    constructor: QorFilterGroup,
+
+    init: function() {
+      let value = this.$element.find('input[name="filter_group"]').val(),
+        group = {};
+
+      this.filters = this.$element.data("filters") || [];
+      this.$builder = this.$element.find(".qor-filter-group__builder");
+      this.labels = this.$builder.data();
+
+      if (value) {
+        try {
+          group = JSON.parse(value);
+        } catch (e) {
+          group = {};
+        }
+      }
+
+      this.$builder.html(this.renderGroup(group, true));
+      this.bind();
+    },
+
+    bind: function() {
+      this.$element
+        .on(EVENT_CLICK, ".qor-filter-group__add-condition", this.addCondition.bind(this))
+        .on(EVENT_CLICK, ".qor-filter-group__add-group", this.addGroup.bind(this))
+        .on(EVENT_CLICK, ".qor-filter-group__remove", this.remove)
+        .on(EVENT_CHANGE, ".qor-filter-group__filter", this.changeFilter.bind(this));
+
+      this.$element.closest("form").on(EVENT_SUBMIT, this.serialize.bind(this));
+    },
+
+    unbind: function() {
+      this.$element.off(EVENT_CLICK).off(EVENT_CHANGE);
+      this.$element.closest("form").off(EVENT_SUBMIT);
+    },
+
+    findFilter: function(name) {
+      return this.filters.filter(function(filter) {
+        return filter.Name === name;
+      })[0];
+    },
+
+    renderGroup: function(group, root) {
+      let labels = this.labels,
+        $group = $(
+          `<div class="qor-filter-group__group">
+            <select class="qor-filter-group__operator">
+              <option value="and">${labels.andLabel}</option>
+              <option value="or">${labels.orLabel}</option>
+            </select>
+            <label><input type="checkbox" class="qor-filter-group__not"> ${labels.notLabel}</label>
+            ${root ? "" : '<button type="button" class="mdl-button mdl-button--icon qor-filter-group__remove"><i class="material-icons">close</i></button>'}
+            <div class="qor-filter-group__items"></div>
+            <button type="button" class="mdl-button qor-filter-group__add-condition">${labels.addConditionLabel}</button>
+            <button type="button" class="mdl-button qor-filter-group__add-group">${labels.addGroupLabel}</button>
+          </div>`
+        ),
+        $items = $group.find(".qor-filter-group__items");
+
+      $group.find(".qor-filter-group__operator").val(group.operator || "and");
+      $group.find(".qor-filter-group__not").prop("checked", !!group.not);
+
+      (group.groups || []).forEach(
+        function(item) {
+          $items.append(item.filter ? this.renderCondition(item) : this.renderGroup(item));
+        }.bind(this)
+      );
+
+      return $group;
+    },
+
+    renderCondition: function(condition) {
+      let $condition = $(
+          `<div class="qor-filter-group__condition">
+            <label><input type="checkbox" class="qor-filter-group__not"> ${this.labels.notLabel}</label>
+            <select class="qor-filter-group__filter"></select>
+            <span class="qor-filter-group__values"></span>
+            <button type="button" class="mdl-button mdl-button--icon qor-filter-group__remove"><i class="material-icons">close</i></button>
+          </div>`
+        ),
+        $select = $condition.find(".qor-filter-group__filter");
+
+      this.filters.forEach(function(filter) {
+        $select.append($("<option>").val(filter.Name).text(filter.Label));
+      });
+
+      $condition.find(".qor-filter-group__not").prop("checked", !!condition.not);
+      $select.val(condition.filter || (this.filters[0] && this.filters[0].Name));
+      this.renderValues($condition, condition.values || {});
+      return $condition;
+    },
+
+    renderValues: function($condition, values) {
+      let filter = this.findFilter($condition.find(".qor-filter-group__filter").val()) || {},
+        operations = filter.Operations || (filter.Type === "string" ? STRING_OPERATIONS : filter.Type === "number" ? NUMBER_OPERATIONS : []),
+        $values = $condition.find(".qor-filter-group__values").empty(),
+        input = function(name) {
+          return $('<input type="text" class="mdl-textfield__input">')
+            .attr("data-name", name)
+            .attr("placeholder", name)
+            .val(values[name] || "");
+        };
+
+      if (operations.length) {
+        let $operation = $('<select data-name="Operation"></select>');
+        operations.forEach(function(operation) {
+          $operation.append($("<option>").val(operation).text(operation));
+        });
+        $operation.val(values.Operation || operations[0]);
+        $values.append($operation);
+      }
+
+      if (filter.Type === "datetime" || filter.Type === "date") {
+        $values.append(input("Start")).append(input("End"));
+      } else if (filter.Type === "number") {
+        $values.append(input("Value")).append(input("Min")).append(input("Max"));
+      } else {
+        $values.append(input("Value"));
+      }
+    },
+
+    addCondition: function(e) {
+      $(e.target)
+        .closest(CLASS_GROUP)
+        .children(".qor-filter-group__items")
+        .append(this.renderCondition({}));
+    },
+
+    addGroup: function(e) {
+      $(e.target)
+        .closest(CLASS_GROUP)
+        .children(".qor-filter-group__items")
+        .append(this.renderGroup({}));
+    },
+
+    remove: function() {
+      $(this)
+        .closest(CLASS_CONDITION + "," + CLASS_GROUP)
+        .remove();
+    },
+
+    changeFilter: function(e) {
+      this.renderValues($(e.target).closest(CLASS_CONDITION), {});
+    },
+
+    toJSON: function($group) {
+      let group = {
+        operator: $group.children(".qor-filter-group__operator").val(),
+        not: $group.children("label").find(".qor-filter-group__not").prop("checked"),
+        groups: []
+      };
+
+      $group
+        .children(".qor-filter-group__items")
+        .children()
+        .each(
+          function(i, item) {
+            let $item = $(item);
+
+            if ($item.is(CLASS_GROUP)) {
+              let subGroup = this.toJSON($item);
+              if (subGroup.groups.length) {
+                group.groups.push(subGroup);
+              }
+              return;
+            }
+
+            let condition = {
+                filter: $item.find(".qor-filter-group__filter").val(),
+                not: $item.find(".qor-filter-group__not").prop("checked"),
+                values: {}
+              },
+              hasValue = false;
+
+            $item.find(".qor-filter-group__values [data-name]").each(function() {
+              let $input = $(this);
+              condition.values[$input.data("name")] = $input.val();
+              hasValue = hasValue || ($input.data("name") !== "Operation" && !!$input.val());
+            });
+
+            if (hasValue) {
+              group.groups.push(condition);
+            }
+          }.bind(this)
+        );
+
+      return group;
+    },
+
+    serialize: function() {
+      let group = this.toJSON(this.$builder.children(CLASS_GROUP)),
+        $input = this.$element.find('input[name="filter_group"]');
+
+      // don't submit blank filter group
+      if (group.groups.length) {
+        $input.val(JSON.stringify(group)).prop("disabled", false);
+      } else {
+        $input.val("").prop("disabled", true);
+      }
+    },
+
+    destroy: function() {
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorFilterGroup.DEFAULTS = {};
+
+  QorFilterGroup.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorFilterGroup(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.filtergroup"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorFilterGroup.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorFilterGroup.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorFilterGroup;
+});
@@ -0,0 +1,129 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.indexsettings",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    EVENT_DRAGSTART = "dragstart." + NAMESPACE,
+    EVENT_DRAGOVER = "dragover." + NAMESPACE,
+    EVENT_DROP = "drop." + NAMESPACE,
+    CLASS_COLUMN = ".qor-index-settings__column";
+
+  function QorIndexSettings(element, options) {
+    this.$element = $(element);
+    this.options = $.extend(
+      {},
+      QorIndexSettings.DEFAULTS,
+      $.isPlainObject(options) && options
+    );
+    this.init();
+  }
+
+  QorIndexSettings.prototype = {
+    constructor: QorIndexSettings,
+
+    init: function() {
+      this.$dropdown = this.$element.find(".qor-index-settings__dropdown");
+      this.bind();
+    },
+
+    bind: function() {
+      this.$element
+        .on(EVENT_CLICK, ".qor-index-settings__toggle", this.toggle.bind(this))
+        .on(EVENT_DRAGSTART, CLASS_COLUMN, this.dragstart.bind(this))
+        .on(EVENT_DRAGOVER, CLASS_COLUMN, this.dragover)
+        .on(EVENT_DROP, CLASS_COLUMN, this.drop.bind(this));
+    },
+
+    unbind: function() {
+      this.$element
+        .off(EVENT_CLICK)
+        .off(EVENT_DRAGSTART)
+        .off(EVENT_DRAGOVER)
+        .off(EVENT_DROP);
+    },
+
+    toggle: function() {
+      this.$dropdown.toggle();
+    },
+
+    dragstart: function(e) {
+      this.$dragging = $(e.target).closest(CLASS_COLUMN);
+    },
+
+    dragover: function(e) {
+      e.preventDefault();
+    },
+
+    drop: function(e) {
+      let $target = $(e.target).closest(CLASS_COLUMN);
+
+      e.preventDefault();
+      if (!this.$dragging || $target.is(this.$dragging)) {
+        return;
+      }
+
+      if (this.$dragging.index() < $target.index()) {
+        $target.after(this.$dragging);
+      } else {
+        $target.before(this.$dragging);
+      }
+      this.$dragging = null;
+    },
+
+    destroy: function() {
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorIndexSettings.DEFAULTS = {};
+
+  QorIndexSettings.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorIndexSettings(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.indexsettings"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorIndexSettings.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorIndexSettings.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorIndexSettings;
+});
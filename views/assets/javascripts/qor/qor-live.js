@@ -0,0 +1,174 @@
+(function(factory) {
+  if (typeof define === "function" && define.amd) {
+    // AMD. Register as anonymous module.
+    define(["jquery"], factory);
+  } else if (typeof exports === "object") {
+    // Node / CommonJS
+    factory(require("jquery"));
+  } else {
+    // Browser globals.
+    factory(jQuery);
+  }
+})(function($) {
+  "use strict";
+
+  let NAMESPACE = "qor.live",
+    EVENT_ENABLE = "enable." + NAMESPACE,
+    EVENT_DISABLE = "disable." + NAMESPACE,
+    EVENT_CLICK = "click." + NAMESPACE,
+    CLASS_LIVE = ".qor-live",
+    CLASS_COUNT = ".qor-live__count",
+    CLASS_REFRESH = ".qor-live__refresh",
+    CLASS_TABLE = ".qor-table-container",
+    CLASS_UPDATED = "qor-live--updated",
+    CLASS_DELETED = "qor-live--deleted";
+
+  function QorLive(element, options) {
+    this.$element = $(element);
+    this.options = $.extend({}, QorLive.DEFAULTS, $.isPlainObject(options) && options);
+    this.init();
+  }
+
+  QorLive.prototype = {
+    constructor: QorLive,
+
+    init: function() {
+      if (!window.EventSource) {
+        return;
+      }
+
+      this.created = [];
+      this.source = new window.EventSource(this.$element.data("url"));
+      this.source.addEventListener("created", this.handle(this.onCreated));
+      this.source.addEventListener("updated", this.handle(this.onUpdated));
+      this.source.addEventListener("deleted", this.handle(this.onDeleted));
+      this.bind();
+    },
+
+    bind: function() {
+      this.$element.on(EVENT_CLICK, CLASS_REFRESH, this.refresh.bind(this));
+    },
+
+    unbind: function() {
+      this.$element.off(EVENT_CLICK);
+    },
+
+    handle: function(fn) {
+      return function(e) {
+        fn.call(this, JSON.parse(e.data));
+      }.bind(this);
+    },
+
+    rows: function(ids) {
+      let $table = this.$element.find(CLASS_TABLE);
+
+      return $.map(ids, function(id) {
+        let $row = $table.find("tr[data-primary-key]").filter(function() {
+          return String($(this).data("primaryKey")) === String(id);
+        });
+        return $row.length ? $row : null;
+      });
+    },
+
+    // count records created by others, the index is refreshed when asked
+    onCreated: function(event) {
+      let created = this.created;
+
+      $.each(event.RecordIDs, function(i, id) {
+        if ($.inArray(id, created) === -1) {
+          created.push(id);
+        }
+      });
+
+      this.$element.find(CLASS_COUNT).text(created.length);
+      this.$element.find(CLASS_LIVE).show();
+    },
+
+    // patch updated rows in place with rows of current index page
+    onUpdated: function(event) {
+      let $rows = this.rows(event.RecordIDs);
+
+      if (!$rows.length) {
+        return;
+      }
+
+      $.get(window.location.href, function(html) {
+        let $table = $("<div>").append($.parseHTML(html)).find(CLASS_TABLE);
+
+        $.each($rows, function(i, $row) {
+          let id = String($row.data("primaryKey")),
+            $newRow = $table.find("tr[data-primary-key]").filter(function() {
+              return String($(this).data("primaryKey")) === id;
+            });
+
+          if ($newRow.length) {
+            $newRow.addClass(CLASS_UPDATED);
+            $row.trigger("disable").replaceWith($newRow);
+            $newRow.trigger("enable");
+            window.setTimeout(function() {
+              $newRow.removeClass(CLASS_UPDATED);
+            }, this.options.highlight);
+          }
+        }.bind(this));
+      }.bind(this));
+    },
+
+    onDeleted: function(event) {
+      $.each(this.rows(event.RecordIDs), function(i, $row) {
+        $row.addClass(CLASS_DELETED).find("input, button, a").attr("tabindex", -1);
+      });
+    },
+
+    refresh: function() {
+      window.location.reload();
+    },
+
+    destroy: function() {
+      if (this.source) {
+        this.source.close();
+      }
+      this.unbind();
+      this.$element.removeData(NAMESPACE);
+    }
+  };
+
+  QorLive.DEFAULTS = {
+    highlight: 3000
+  };
+
+  QorLive.plugin = function(options) {
+    return this.each(function() {
+      let $this = $(this),
+        data = $this.data(NAMESPACE),
+        fn;
+
+      if (!data) {
+        if (/destroy/.test(options)) {
+          return;
+        }
+
+        $this.data(NAMESPACE, (data = new QorLive(this, options)));
+      }
+
+      if (typeof options === "string" && $.isFunction((fn = data[options]))) {
+        fn.apply(data);
+      }
+    });
+  };
+
+  $(function() {
+    let selector = '[data-toggle="qor.live"]',
+      options;
+
+    $(document)
+      .on(EVENT_DISABLE, function(e) {
+        QorLive.plugin.call($(selector, e.target), "destroy");
+      })
+      .on(EVENT_ENABLE, function(e) {
+        QorLive.plugin.call($(selector, e.target), options);
+      })
+      .triggerHandler(EVENT_ENABLE);
+  });
+
+  return QorLive;
+});
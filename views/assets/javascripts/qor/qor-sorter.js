@@ -44,9 +44,10 @@
 
     sort: function (e) {
       var $target = $(e.currentTarget);
-      var orderBy = $target.data('orderBy');
+      // Hold shift key to sort with several columns
+      var orderBy = e.shiftKey && $target.data('orderByAppend') || $target.data('orderBy');
       var search = location.search;
-      var param = 'order_by=' + orderBy;
+      var param = 'order_by=' + encodeURIComponent(orderBy);
 
       // Stop when it is not sortable
       if (!orderBy) {
@@ -54,7 +55,7 @@
       }
 
       if (/order_by/.test(search)) {
-        search = search.replace(/order_by(=\w+)?/, function () {
+        search = search.replace(/order_by(=[^&]*)?/, function () {
           return param;
         });
       } else {
@@ -0,0 +1,539 @@
+package admin
+
+import (
+	"bytes"
+	stdcontext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simonedbarber/qor"
+	"github.com/simonedbarber/qor/resource"
+	"github.com/simonedbarber/qor/utils"
+	"github.com/simonedbarber/roles"
+	"gorm.io/gorm"
+)
+
+// events of webhooks besides LiveEventCreated, LiveEventUpdated, LiveEventDeleted
+const (
+	WebhookEventAction = "action"
+	WebhookEventTest   = "test"
+)
+
+// status of webhook deliveries
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// headers of webhook requests, signature is hex encoded HMAC-SHA256 of "<timestamp>.<body>" with webhook's secret, prefixed with "sha256="
+const (
+	WebhookEventHeader     = "X-Qor-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Qor-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Qor-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Qor-Webhook-Signature"
+)
+
+// WebhookConfig webhooks configuration, refer Admin.UseWebhooks
+type WebhookConfig struct {
+	// Client http client sends webhooks, default timeout is 10 seconds
+	Client *http.Client
+	// MaxAttempts deliveries are failed after attempts, default is 6
+	MaxAttempts int
+	// Backoff delay before next attempt after failed attempts, default is WebhookBackoff
+	Backoff func(attempts int) time.Duration
+	// RetryInterval interval of checking deliveries to retry, default is 30 seconds
+	RetryInterval time.Duration
+	// Menu menu of webhooks and deliveries
+	Menu []string
+	// Permission permission of managing webhooks, replaying deliveries, default only allows role admin
+	Permission *roles.Permission
+}
+
+// WebhookBackoff default backoff of webhook retries, 30 seconds doubled with each failed attempt, at most 6 hours
+func WebhookBackoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < 6*time.Hour; i++ {
+		delay *= 2
+	}
+
+	if delay > 6*time.Hour {
+		delay = 6 * time.Hour
+	}
+	return delay
+}
+
+// UseWebhooks send webhooks to subscribers when records are created, updated, deleted, or actions are executed, e.g:
+//
+//	Admin.UseWebhooks(&admin.WebhookConfig{Menu: []string{"Settings"}})
+//
+// It adds resource Webhook to subscribe events of resources, and resource Webhook Delivery to view and replay deliveries, payloads of records are their JSON of show page,
+// failed deliveries are retried with Admin.RunWebhookRetries
+func (admin *Admin) UseWebhooks(config *WebhookConfig) {
+	if config == nil {
+		config = &WebhookConfig{}
+	}
+
+	if admin.DB == nil {
+		utils.ExitWithMsg("webhooks require admin's DB")
+	}
+
+	if err := admin.DB.AutoMigrate(&QorAdminWebhook{}, &QorAdminWebhookDelivery{}); err != nil {
+		utils.ExitWithMsg(err)
+	}
+
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 6
+	}
+
+	if config.Backoff == nil {
+		config.Backoff = WebhookBackoff
+	}
+
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 30 * time.Second
+	}
+
+	if config.Permission == nil {
+		config.Permission = roles.Allow(roles.CRUD, "admin")
+	}
+	admin.webhooks = config
+
+	webhook := admin.AddResource(&QorAdminWebhook{}, &Config{Name: "Webhook", Menu: config.Menu, Permission: config.Permission})
+	webhook.Meta(&Meta{Name: "Resource", Type: "select_one", Config: &SelectOneConfig{
+		Collection: func(_ interface{}, context *Context) (results [][]string) {
+			for _, res := range admin.GetResources() {
+				if !res.Config.Invisible && res != webhook && res.Value != nil {
+					results = append(results, []string{res.ToParam(), res.Name})
+				}
+			}
+			return
+		},
+	}})
+	webhook.IndexAttrs("Name", "Resource", "URL", "OnCreate", "OnUpdate", "OnDelete", "OnAction", "Enabled")
+	webhook.NewAttrs("Name", "Resource", "URL", "Secret", "OnCreate", "OnUpdate", "OnDelete", "OnAction", "Enabled")
+	webhook.EditAttrs("Name", "Resource", "URL", "Secret", "OnCreate", "OnUpdate", "OnDelete", "OnAction", "Enabled")
+	webhook.ShowAttrs("Name", "Resource", "URL", "OnCreate", "OnUpdate", "OnDelete", "OnAction", "Enabled")
+	// secret is write-only, it isn't shown in forms or JSON, the secret is kept if it is left blank when editing
+	webhook.Meta(&Meta{
+		Name: "Secret",
+		Type: "password",
+		Valuer: func(interface{}, *qor.Context) interface{} {
+			return ""
+		},
+		Setter: func(record interface{}, metaValue *resource.MetaValue, context *qor.Context) {
+			if hook, ok := record.(*QorAdminWebhook); ok {
+				if secret := utils.ToString(metaValue.Value); secret != "" {
+					hook.Secret = secret
+				}
+			}
+		},
+	})
+	webhook.Action(&Action{
+		Name:       "Test Webhook",
+		Label:      "Test",
+		Permission: config.Permission,
+		Modes:      []string{"menu_item", "edit", "show"},
+		Handler: func(argument *ActionArgument) error {
+			for _, primaryValue := range argument.PrimaryValues {
+				var hook QorAdminWebhook
+				if err := admin.DB.First(&hook, "id = ?", primaryValue).Error; err != nil {
+					return err
+				}
+
+				if _, err := argument.Context.testWebhook(hook); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	permission := roles.Deny(roles.Create, roles.Anyone).Deny(roles.Update, roles.Anyone)
+	permission = config.Permission.Concat(permission)
+
+	delivery := admin.AddResource(&QorAdminWebhookDelivery{}, &Config{Name: "Webhook Delivery", Menu: config.Menu, Permission: permission})
+	delivery.IndexAttrs("ID", "CreatedAt", "WebhookID", "Event", "Resource", "RecordIDs", "Status", "Attempts", "ResponseStatus", "NextAttemptAt")
+	delivery.ShowAttrs("ID", "CreatedAt", "WebhookID", "Event", "Resource", "RecordIDs", "Status", "Attempts", "ResponseStatus", "Response", "Error", "NextAttemptAt", "DeliveredAt", "Payload")
+	delivery.Action(&Action{
+		Name:       "Replay Webhook Delivery",
+		Label:      "Replay",
+		Permission: config.Permission,
+		Modes:      []string{"batch", "menu_item", "show"},
+		Handler: func(argument *ActionArgument) error {
+			for _, primaryValue := range argument.PrimaryValues {
+				if _, err := admin.replayWebhookDelivery(primaryValue); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+}
+
+// QorAdminWebhook subscription of a resource's events
+type QorAdminWebhook struct {
+	gorm.Model
+	Name     string
+	Resource string `gorm:"size:128;index"`
+	URL      string
+	// Secret key of HMAC signatures, it is write-only in admin, subscribers verify signatures with the same secret
+	Secret   string
+	OnCreate bool
+	OnUpdate bool
+	OnDelete bool
+	OnAction bool
+	Enabled  bool
+}
+
+// BeforeSave validate URL and secret of the webhook, secret is required as it can't be read from admin
+func (webhook *QorAdminWebhook) BeforeSave(tx *gorm.DB) error {
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		return errors.New("webhook's secret can't be blank")
+	}
+	return nil
+}
+
+// validateWebhookURL check webhook's URL is an absolute http or https URL
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook's URL %q should be an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// Subscribes check if the webhook subscribes the event
+func (webhook QorAdminWebhook) Subscribes(event string) bool {
+	if !webhook.Enabled {
+		return false
+	}
+
+	switch event {
+	case LiveEventCreated:
+		return webhook.OnCreate
+	case LiveEventUpdated:
+		return webhook.OnUpdate
+	case LiveEventDeleted:
+		return webhook.OnDelete
+	case WebhookEventAction:
+		return webhook.OnAction
+	case WebhookEventTest:
+		return true
+	}
+	return false
+}
+
+// QorAdminWebhookDelivery a webhook request and its attempts
+type QorAdminWebhookDelivery struct {
+	gorm.Model
+	WebhookID      uint `gorm:"index"`
+	Event          string
+	Resource       string
+	RecordIDs      string
+	Payload        string `gorm:"type:text"`
+	Status         string `gorm:"size:32;index"`
+	Attempts       int
+	ResponseStatus int
+	Response       string     `gorm:"size:1024"`
+	Error          string     `gorm:"size:1024"`
+	NextAttemptAt  *time.Time `gorm:"index"`
+	DeliveredAt    *time.Time
+}
+
+// WebhookPayload body of webhook requests, records are JSON of their show page
+type WebhookPayload struct {
+	Event     string
+	Resource  string
+	Action    string `json:",omitempty"`
+	RecordIDs []string
+	Records   []interface{}
+	UserID    string `json:",omitempty"`
+	UserName  string `json:",omitempty"`
+	Timestamp time.Time
+}
+
+// SignWebhook return signature of webhook's body sent at timestamp, receivers could verify header X-Qor-Webhook-Signature with it
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// subscribedWebhooks return enabled webhooks of the resource subscribed the event
+func (admin *Admin) subscribedWebhooks(res *Resource, event string) (webhooks []QorAdminWebhook) {
+	if admin.webhooks == nil || res == nil {
+		return nil
+	}
+
+	var candidates []QorAdminWebhook
+	if err := admin.DB.Where("resource = ? AND enabled = ?", res.ToParam(), true).Find(&candidates).Error; err != nil {
+		return nil
+	}
+
+	for _, webhook := range candidates {
+		if webhook.Subscribes(event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
+
+// newWebhookPayload build payload of records with JSON of their show page
+func (context *Context) newWebhookPayload(res *Resource, event string, records []interface{}) WebhookPayload {
+	payload := WebhookPayload{Event: event, Resource: res.ToParam(), RecordIDs: []string{}, Records: []interface{}{}, Timestamp: time.Now()}
+	for _, record := range records {
+		payload.RecordIDs = append(payload.RecordIDs, fmt.Sprint(context.primaryKeyOf(record)))
+		payload.Records = append(payload.Records, convertObjectToJSONMap(res, context, record, "show"))
+	}
+
+	if context.CurrentUser != nil {
+		payload.UserID = fmt.Sprint(context.CurrentUser.GetID())
+		payload.UserName = context.CurrentUser.DisplayName()
+	}
+	return payload
+}
+
+// triggerWebhooks send webhooks of the event to subscribers of the resource in background, errors are saved in deliveries
+func (context *Context) triggerWebhooks(res *Resource, event string, records ...interface{}) {
+	if len(records) == 0 {
+		return
+	}
+
+	if webhooks := context.Admin.subscribedWebhooks(res, event); len(webhooks) > 0 {
+		context.Admin.enqueueWebhooks(webhooks, context.newWebhookPayload(res, event, records))
+	}
+}
+
+// triggerActionWebhooks send webhooks of the executed action, records deleted by the action are only included in RecordIDs
+func (context *Context) triggerActionWebhooks(action *Action, primaryValues []string) {
+	res := context.Resource
+	webhooks := context.Admin.subscribedWebhooks(res, WebhookEventAction)
+	if len(webhooks) == 0 {
+		return
+	}
+
+	var records []interface{}
+	for _, primaryValue := range context.unpublishedLiveEventIDs(primaryValues) {
+		if found, err := res.findDeletingRecords([]string{primaryValue}, context.Context); err == nil {
+			records = append(records, found...)
+		}
+	}
+
+	payload := context.newWebhookPayload(res, WebhookEventAction, records)
+	payload.Action = action.Name
+	payload.RecordIDs = primaryValues
+	context.Admin.enqueueWebhooks(webhooks, payload)
+}
+
+// testWebhook send a test payload to the webhook right away, return the delivery and its error
+func (context *Context) testWebhook(webhook QorAdminWebhook) (QorAdminWebhookDelivery, error) {
+	payload := WebhookPayload{Event: WebhookEventTest, Resource: webhook.Resource, RecordIDs: []string{}, Records: []interface{}{}, Timestamp: time.Now()}
+	if context.CurrentUser != nil {
+		payload.UserID = fmt.Sprint(context.CurrentUser.GetID())
+		payload.UserName = context.CurrentUser.DisplayName()
+	}
+
+	deliveries, err := context.Admin.createWebhookDeliveries([]QorAdminWebhook{webhook}, payload)
+	if err != nil {
+		return QorAdminWebhookDelivery{}, err
+	}
+	return context.Admin.deliverWebhook(deliveries[0].ID)
+}
+
+// createWebhookDeliveries save pending deliveries of the payload for webhooks
+func (admin *Admin) createWebhookDeliveries(webhooks []QorAdminWebhook, payload WebhookPayload) ([]QorAdminWebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		now        = time.Now()
+		deliveries []QorAdminWebhookDelivery
+	)
+
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, QorAdminWebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         payload.Event,
+			Resource:      payload.Resource,
+			RecordIDs:     strings.Join(payload.RecordIDs, ","),
+			Payload:       string(body),
+			Status:        WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	return deliveries, admin.DB.Create(&deliveries).Error
+}
+
+// enqueueWebhooks save deliveries of the payload and send them in background
+func (admin *Admin) enqueueWebhooks(webhooks []QorAdminWebhook, payload WebhookPayload) {
+	deliveries, err := admin.createWebhookDeliveries(webhooks, payload)
+	if err != nil {
+		return
+	}
+
+	for _, delivery := range deliveries {
+		go admin.deliverWebhook(delivery.ID)
+	}
+}
+
+// newWebhookRequest build signed request of the delivery
+func newWebhookRequest(webhook QorAdminWebhook, delivery QorAdminWebhookDelivery, now time.Time) (*http.Request, error) {
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return nil, err
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "QOR-Admin-Webhook")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, fmt.Sprint(delivery.ID))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, timestamp, body))
+	return req, nil
+}
+
+// deliverWebhook attempt to send the pending delivery, it is claimed before sending so it won't be sent by others at the same time, failed attempts are retried with backoff until MaxAttempts
+func (admin *Admin) deliverWebhook(id uint) (delivery QorAdminWebhookDelivery, err error) {
+	config := admin.webhooks
+	now := time.Now()
+	claimed := now.Add(config.Client.Timeout + time.Minute)
+
+	result := admin.DB.Model(&QorAdminWebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, WebhookDeliveryPending, now).
+		Update("next_attempt_at", claimed)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return delivery, result.Error
+	}
+
+	if err := admin.DB.First(&delivery, id).Error; err != nil {
+		return delivery, err
+	}
+
+	var webhook QorAdminWebhook
+	if err = admin.DB.First(&webhook, delivery.WebhookID).Error; err == nil {
+		var (
+			req  *http.Request
+			resp *http.Response
+		)
+
+		if req, err = newWebhookRequest(webhook, delivery, now); err == nil {
+			if resp, err = config.Client.Do(req); err == nil {
+				body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+				resp.Body.Close()
+
+				delivery.ResponseStatus = resp.StatusCode
+				delivery.Response = string(body)
+				if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+					err = fmt.Errorf("webhook responded with status %v", resp.StatusCode)
+				}
+			}
+		}
+	}
+
+	delivery.Attempts++
+	delivery.Error = ""
+	delivery.NextAttemptAt = nil
+	if err == nil {
+		delivery.Status = WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.Error = err.Error()
+		if len(delivery.Error) > 1024 {
+			delivery.Error = delivery.Error[:1024]
+		}
+
+		if delivery.Attempts >= config.MaxAttempts || errors.Is(err, gorm.ErrRecordNotFound) {
+			delivery.Status = WebhookDeliveryFailed
+		} else {
+			next := now.Add(config.Backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if saveErr := admin.DB.Select("*").Save(&delivery).Error; saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return delivery, err
+}
+
+// replayWebhookDelivery send payload of the delivery again with a new delivery
+func (admin *Admin) replayWebhookDelivery(id string) (QorAdminWebhookDelivery, error) {
+	var delivery QorAdminWebhookDelivery
+	if err := admin.DB.First(&delivery, "id = ?", id).Error; err != nil {
+		return delivery, err
+	}
+
+	now := time.Now()
+	replay := QorAdminWebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		Event:         delivery.Event,
+		Resource:      delivery.Resource,
+		RecordIDs:     delivery.RecordIDs,
+		Payload:       delivery.Payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+
+	if err := admin.DB.Create(&replay).Error; err != nil {
+		return replay, err
+	}
+	return admin.deliverWebhook(replay.ID)
+}
+
+// RunWebhookRetries send pending webhook deliveries whose next attempts are due every WebhookConfig.RetryInterval until ctx is done, e.g:
+//
+//	go Admin.RunWebhookRetries(ctx)
+func (admin *Admin) RunWebhookRetries(ctx stdcontext.Context) {
+	if admin.webhooks == nil {
+		return
+	}
+
+	ticker := time.NewTicker(admin.webhooks.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var ids []uint
+		admin.DB.WithContext(ctx).Model(&QorAdminWebhookDelivery{}).
+			Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryPending, time.Now()).
+			Limit(100).Pluck("id", &ids)
+
+		for _, id := range ids {
+			admin.deliverWebhook(id)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	expected := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		6:  16 * time.Minute,
+		20: 6 * time.Hour,
+	}
+
+	for attempts, delay := range expected {
+		if got := WebhookBackoff(attempts); got != delay {
+			t.Errorf("backoff after %v attempts should be %v, but got %v", attempts, delay, got)
+		}
+	}
+}
+
+func TestWebhookSubscribes(t *testing.T) {
+	webhook := QorAdminWebhook{OnCreate: true, OnAction: true, Enabled: true}
+	for event, subscribed := range map[string]bool{
+		LiveEventCreated:   true,
+		LiveEventUpdated:   false,
+		LiveEventDeleted:   false,
+		WebhookEventAction: true,
+		WebhookEventTest:   true,
+	} {
+		if webhook.Subscribes(event) != subscribed {
+			t.Errorf("subscription of %v should be %v", event, subscribed)
+		}
+	}
+
+	webhook.Enabled = false
+	if webhook.Subscribes(LiveEventCreated) {
+		t.Errorf("disabled webhook shouldn't subscribe events")
+	}
+}
+
+func TestNewWebhookRequest(t *testing.T) {
+	var (
+		now      = time.Unix(1700000000, 0)
+		webhook  = QorAdminWebhook{URL: "https://example.com/hooks", Secret: "secret"}
+		delivery = QorAdminWebhookDelivery{Event: LiveEventUpdated, Payload: `{"Event":"updated"}`}
+	)
+	delivery.ID = 3
+
+	req, err := newWebhookRequest(webhook, delivery, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != delivery.Payload {
+		t.Errorf("body should be the payload, but got %v", string(body))
+	}
+
+	if req.Header.Get(WebhookEventHeader) != LiveEventUpdated || req.Header.Get(WebhookDeliveryHeader) != "3" {
+		t.Errorf("unexpected headers %v", req.Header)
+	}
+
+	timestamp, _ := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if timestamp != now.Unix() {
+		t.Errorf("timestamp should be %v, but got %v", now.Unix(), timestamp)
+	}
+
+	signature := req.Header.Get(WebhookSignatureHeader)
+	if signature != SignWebhook("secret", timestamp, body) {
+		t.Errorf("signature %v doesn't match", signature)
+	}
+
+	if signature == SignWebhook("another secret", timestamp, body) || signature == SignWebhook("secret", timestamp+1, body) {
+		t.Errorf("signature should depend on secret and timestamp")
+	}
+
+	// echo -n '1700000000.{"Event":"updated"}' | openssl dgst -sha256 -hmac secret
+	if expected := "sha256=04f086bee90cca0aa4ff1c313954229fa66fc79341ac8682a08bfdd99d16b231"; signature != expected {
+		t.Errorf("signature should be %v, but got %v", expected, signature)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for rawURL, valid := range map[string]bool{
+		"https://example.com/hooks":  true,
+		"http://example.com:8080/":   true,
+		"file:///etc/passwd":         false,
+		"gopher://example.com/hooks": false,
+		"/hooks":                     false,
+		"":                           false,
+	} {
+		if err := validateWebhookURL(rawURL); (err == nil) != valid {
+			t.Errorf("webhook URL %q should be valid: %v, but got error %v", rawURL, valid, err)
+		}
+	}
+}
+
+func TestWebhookBeforeSave(t *testing.T) {
+	if err := (&QorAdminWebhook{URL: "https://example.com/hooks"}).BeforeSave(nil); err == nil {
+		t.Errorf("webhook without secret shouldn't be saved")
+	}
+
+	if err := (&QorAdminWebhook{URL: "https://example.com/hooks", Secret: "secret"}).BeforeSave(nil); err != nil {
+		t.Errorf("webhook with URL and secret should be saved, got %v", err)
+	}
+}